@@ -1,68 +1,206 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"path/filepath"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
 	"sync"
 	"time"
 
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/util/homedir"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type AppState struct {
 	useNewTerminal          bool
+	showTimestamps          bool
 	selectedNamespace       string
 	selectedContext         string
+	serverHost              string            // API server of the selected context
+	contextOrigins          map[string]string // kubeconfig file defining each context
+	selectedKind            string
 	namespaceOptions        []string
+	namespaceNames          []string
 	contextOptions          []string
 	namespaceExpansionState map[string]bool
+	contextExpansionStates  map[string]map[string]bool // by context name
 	lastRefreshed           string
 	modalActive             bool
 	isPodHighlighted        bool
+	phaseFilter             v1.PodPhase
+	globalSearch            bool
+	fuzzySearch             bool
+	podSort                 podSortMode
+	topPodsSort             podSortMode
+	metadataMode            metadataMode
 	kubeconfig              *string
+	windowsShell            *string
+	terminalCmd             *string
+	tmuxMode                *string
+	tailLines               *int
+	outputDir               *string
+	nodeFilter              *string
+	startNamespace          *string
+	startContext            *string
+	refreshInterval         *time.Duration
+	vimMode                 *bool
+	keyBindings             []keyBinding
+	keyActions              map[rune]action
+	config                  Config
+	savedState              SavedState
+	theme                   theme
+	mouse                   *bool
+	readOnly                *bool
+	noConfirmQuit           *bool
+	preview                 *bool
 
 	app               *tview.Application
 	treeView          *tview.TreeView
 	helperText        *tview.TextView
+	statusBar         *tview.TextView
 	searchInput       *tview.InputField
 	secondSection     *tview.TextView
 	namespaceDropdown *tview.DropDown
 	contextDropdown   *tview.DropDown
+	kindDropdown      *tview.DropDown
 	modal             *tview.Modal
 	grid              *tview.Grid
 	pages             *tview.Pages
 
-	clientset       *kubernetes.Clientset
-	dynamicClient   dynamic.Interface
+	clientset     *kubernetes.Clientset
+	dynamicClient dynamic.Interface
+	// metricsClient is nil when the cluster doesn't serve the metrics API,
+	// i.e. runs no metrics-server
 	metricsClient   *metrics.Clientset
 	k8sClientsReady chan struct{}
 
 	metricsModalOpen bool
+	metricsHistory   map[string][]metricsSample
+
+	podWatchStop        chan struct{}
+	podDetailsCancel    context.CancelFunc
+	refreshCancel       context.CancelFunc
+	logStreamCancel     context.CancelFunc
+	logStreamDesc       string
+	logStreamID         int
+	outputCommandCancel context.CancelFunc
+	copyCancel          context.CancelFunc
+	copyDesc            string
+	portForwardCmd      *exec.Cmd
+	portForwardDesc     string
+
+	statusMessage string
+	statusLevel   statusLevel
+
+	// graphRender draws the graphs in the output section at a given width,
+	// while they are displayed
+	graphRender        func(width int) string
+	graphRenderedWidth int
+
+	outputLabel      string
+	outputSearchTerm string
+	outputMatchCount int
+	outputMatchIndex int
 
 	mu sync.Mutex
 
-	promClient    promv1.API
-	promDetected  bool
-	prometheusURL *string
+	hiddenNamespaces     []string
+	showHiddenNamespaces bool
+
+	logFile   *string
+	logger    *slog.Logger
+	logOutput *os.File
+	logRing   *logRing
+
+	graphRange          graphRange
+	lastPromQL          string
+	promClient          promv1.API
+	promDetected        bool
+	prometheusURL       *string
+	prometheusToken     *string
+	prometheusBasicAuth *string
+	prometheusInsecure  *bool
 }
 
 func (state *AppState) initializeApp() {
 	state.app = tview.NewApplication()
 
-	if home := homedir.HomeDir(); home != "" {
-		state.kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		state.kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+	// The config file provides the flag defaults, so flags still override it
+	config, err := loadConfig(configPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "podminator: %v\n", err)
+		os.Exit(1)
+	}
+	state.config = config
+	state.hiddenNamespaces = defaultHiddenNamespaces
+	if config.HiddenNamespaces != nil {
+		state.hiddenNamespaces = config.HiddenNamespaces
+	}
+	state.savedState = loadSavedState(statePath())
+
+	state.kubeconfig = flag.String("kubeconfig", config.Kubeconfig, "(optional) absolute path to the kubeconfig file, by default the files in $KUBECONFIG are merged, or ~/.kube/config is used")
+
+	refreshInterval := 60 * time.Second
+	if config.RefreshInterval != nil {
+		refreshInterval = config.RefreshInterval.Duration
 	}
 
-	state.prometheusURL = flag.String("prometheus-url", "", "(optional) URL of the Prometheus server (e.g., http://localhost:9090)")
+	defaultThemeName := "dark"
+	if config.Theme != "" {
+		defaultThemeName = config.Theme
+	}
+
+	state.terminalCmd = flag.String("terminal-cmd", config.TerminalCmd, "(optional) command template used to open a new terminal, e.g. 'wezterm start -- bash -c {{.Command}}'")
+	state.startContext = flag.String("context", "", "(optional) kubeconfig context to open at startup instead of the current one")
+	state.startNamespace = flag.String("namespace", config.Namespace, "(optional) namespace to open at startup, or 'all'")
+	state.nodeFilter = flag.String("node", "", "(optional) only show pods scheduled on this node")
+	state.refreshInterval = flag.Duration("refresh-interval", refreshInterval, "(optional) how often the tree is fully refreshed, e.g. 30s or 5m, 0 to only refresh with 'r'")
+	state.vimMode = flag.Bool("vim", config.Vim, "(optional) navigate with h/j/k/l in the tree and output; '.' then opens logs and '%' graphs")
+	state.readOnly = flag.Bool("read-only", false, "(optional) turn off the actions that change the cluster or run commands in it: exec, debug, copy, delete, restart and scale")
+	state.preview = flag.Bool("preview", config.Preview, "(optional) show the kubectl command behind exec, copy, debug, delete, restart and scale, and ask before running it")
+	state.noConfirmQuit = flag.Bool("no-confirm-quit", false, "(optional) quit right away, without asking first when a port-forward or log stream is running")
+	state.mouse = flag.Bool("mouse", config.Mouse, "(optional) select and scroll with the mouse; hold Shift to select text for copying in most terminals")
+	themeName := flag.String("theme", defaultThemeName, "(optional) color theme: dark, light or solarized")
+	state.outputDir = flag.String("output-dir", ".", "(optional) directory where 'w' saves the output section")
+	state.tailLines = flag.Int("tail-lines", 1000, "(optional) number of recent log lines to show, 0 for the whole log")
+	state.tmuxMode = flag.String("tmux", "split", "(optional) when running inside tmux, open tail and exec in a 'split' pane, a new 'window', or 'off' to use a new terminal")
+	state.logFile = flag.String("log-file", config.LogFile, "(optional) file where Podminator writes its own diagnostics, e.g. client errors and Prometheus warnings")
+	state.windowsShell = flag.String("windows-shell", "powershell", "(optional) shell used to run commands on Windows: powershell or bash (Git Bash/WSL)")
+
+	state.prometheusURL = flag.String("prometheus-url", config.PrometheusURL, "(optional) URL of the Prometheus server (e.g., http://localhost:9090)")
+	state.prometheusToken = flag.String("prometheus-token", "", "(optional) bearer token sent to the Prometheus server")
+	state.prometheusBasicAuth = flag.String("prometheus-basic-auth", "", "(optional) 'user:password' sent to the Prometheus server as basic auth")
+	state.prometheusInsecure = flag.Bool("prometheus-insecure-skip-verify", false, "(optional) skip TLS certificate verification for the Prometheus server")
+	prometheusRange := flag.Duration("prometheus-range", 8*time.Hour, "(optional) time range of the 'h' graphs, e.g. 15m, 6h or 24h")
+	prometheusStep := flag.Duration("prometheus-step", 0, "(optional) resolution of the 'h' graphs, picked from the range when 0")
 
 	flag.Parse()
 
+	state.keyBindings, state.keyActions, err = resolveKeyBindings(config.Keybindings, *state.vimMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "podminator: invalid config file %s: %v\n", configPath(), err)
+		os.Exit(1)
+	}
+
+	state.graphRange = graphRange{window: *prometheusRange, step: *prometheusStep}
+
+	state.theme, err = lookupTheme(*themeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "podminator: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := state.openLog(*state.logFile); err != nil {
+		fmt.Fprintf(os.Stderr, "podminator: opening the log file: %v\n", err)
+		os.Exit(1)
+	}
+
 	state.lastRefreshed = time.Now().Format("15:04:05")
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	v1 "k8s.io/api/core/v1"
+)
+
+// clipboardCommands are tried in order to copy text on the local machine.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copyToClipboard puts text on the clipboard with the first clipboard tool
+// found. Over SSH, or when there is none, it sends an OSC 52 escape sequence
+// so the terminal emulator sets its own clipboard.
+func copyToClipboard(text string) error {
+	if os.Getenv("SSH_TTY") == "" {
+		for _, args := range clipboardCommands {
+			if runtime.GOOS == "windows" && args[0] != "clip.exe" {
+				continue
+			}
+			path, err := exec.LookPath(args[0])
+			if err != nil {
+				continue
+			}
+			cmd := exec.Command(path, args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("%s: %v: %s", args[0], err, strings.TrimSpace(string(output)))
+			}
+			return nil
+		}
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	_, err := seq.WriteTo(os.Stdout)
+	return err
+}
+
+// showCopyCommandModal offers the actions on pod, and copies the kubectl
+// command equivalent to the one picked, so it can be pasted into a script.
+func (state *AppState) showCopyCommandModal(pod *v1.Pod) {
+	copyCommand := func(command string) {
+		if err := copyToClipboard(command); err != nil {
+			state.setStatus(fmt.Sprintf("Could not copy to the clipboard (%v): %s", err, command), statusWarning)
+			return
+		}
+		state.setStatus("Copied: "+command, statusSuccess)
+	}
+
+	choices := []string{"Logs", "Exec", "Describe", "YAML", "Events", "Delete"}
+	state.showChoiceModal(fmt.Sprintf("Copy the kubectl command for which action on pod '%s'?", pod.Name), choices, func(index int) {
+		switch choices[index] {
+		case "Logs":
+			state.selectContainer(pod, true, true, func(containerName string) {
+				copyCommand(state.logsCommand(pod.Name, pod.Namespace, containerName, logOptions{}))
+			})
+		case "Exec":
+			state.selectContainer(pod, false, false, func(containerName string) {
+				copyCommand(state.execCommand(pod.Name, pod.Namespace, containerName, execShells[len(execShells)-1]))
+			})
+		case "Describe":
+			copyCommand(state.describeCommand(pod))
+		case "YAML":
+			copyCommand(state.yamlCommand(pod))
+		case "Events":
+			copyCommand(state.kubectlCommand("events", "--for=pod/"+pod.Name, "--namespace="+pod.Namespace))
+		case "Delete":
+			copyCommand(state.deleteCommand(pod))
+		}
+	})
+}
+
+// showCopyFieldModal offers the fields of pod that can be copied to the
+// clipboard, and copies the one picked.
+func (state *AppState) showCopyFieldModal(pod *v1.Pod) {
+	fields := []struct{ label, value string }{
+		{"Name", pod.Name},
+		{"IP", pod.Status.PodIP},
+		{"Node", pod.Spec.NodeName},
+	}
+	var buttons []string
+	for _, field := range fields {
+		buttons = append(buttons, field.label)
+	}
+
+	state.showChoiceModal(fmt.Sprintf("Copy which value of pod '%s'?", pod.Name), buttons, func(index int) {
+		field := fields[index]
+		if field.value == "" {
+			state.setStatus(fmt.Sprintf("Pod '%s' has no %s yet", pod.Name, field.label), statusWarning)
+			return
+		}
+		if err := copyToClipboard(field.value); err != nil {
+			state.setStatus(fmt.Sprintf("Error copying to the clipboard: %v", err), statusError)
+			return
+		}
+		state.setStatus(fmt.Sprintf("Copied %s '%s' to the clipboard", strings.ToLower(field.label), field.value), statusSuccess)
+	})
+}
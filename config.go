@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
+)
+
+// Config holds the preferences read from the config file. Every field is
+// optional and only changes the default of the matching flag, so flags
+// passed on the command line still win.
+type Config struct {
+	Kubeconfig      string           `json:"kubeconfig,omitempty"`
+	PrometheusURL   string           `json:"prometheusURL,omitempty"`
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	Namespace       string           `json:"namespace,omitempty"`
+	TerminalCmd     string           `json:"terminalCmd,omitempty"`
+	Vim             bool             `json:"vim,omitempty"`
+	Theme           string           `json:"theme,omitempty"`
+	Mouse           bool             `json:"mouse,omitempty"`
+	Preview         bool             `json:"preview,omitempty"`
+	LogFile         string           `json:"logFile,omitempty"`
+
+	// These have no matching flag. FavoriteNamespaces is also updated by
+	// Podminator itself when 'b' is pressed, and HiddenNamespaces replaces
+	// defaultHiddenNamespaces when set.
+	Keybindings        map[string]string `json:"keybindings,omitempty"`
+	FavoriteNamespaces []string          `json:"favoriteNamespaces,omitempty"`
+	HiddenNamespaces   []string          `json:"hiddenNamespaces,omitempty"`
+}
+
+// defaultHiddenNamespaces are the system namespaces left out when listing
+// every namespace, until revealed.
+var defaultHiddenNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// configPath returns where the config file lives, or "" when there is no
+// home directory.
+func configPath() string {
+	home := homedir.HomeDir()
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "podminator", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error
+// and yields an empty Config.
+func loadConfig(path string) (Config, error) {
+	var config Config
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, err
+	}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// saveConfig writes config to path, creating its directory if needed. The
+// file is rewritten from config, so comments in it are not kept.
+func saveConfig(path string, config Config) error {
+	if path == "" {
+		return fmt.Errorf("no home directory to save the config file in")
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SavedState is where the previous session left off, restored on the next
+// start.
+type SavedState struct {
+	Context   string `json:"context,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// statePath returns where the saved state lives, next to the config file.
+func statePath() string {
+	home := homedir.HomeDir()
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "podminator", "state.yaml")
+}
+
+// loadSavedState reads the state saved by the previous session. A missing or
+// unreadable file just means there is nothing to restore.
+func loadSavedState(path string) SavedState {
+	var saved SavedState
+	if path == "" {
+		return saved
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return saved
+	}
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		return SavedState{}
+	}
+	return saved
+}
+
+// saveState records the current context and namespace for the next session.
+func saveState(path string, saved SavedState) error {
+	if path == "" {
+		return nil
+	}
+	data, err := yaml.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+)
+
+// newConfigNode creates the tree node for a ConfigMap or Secret, showing
+// how many keys it holds.
+func (state *AppState) newConfigNode(object interface{}, name string, keys int, created string) *tview.TreeNode {
+	text := fmt.Sprintf("%s %d keys [::d]%s[::-]", name, keys, created)
+	return tview.NewTreeNode(text).SetReference(object).SetColor(state.theme.success)
+}
+
+func formatConfigMapDetails(configMap *v1.ConfigMap) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]ConfigMap Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", configMap.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", configMap.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(configMap.CreationTimestamp.Time)))
+
+	sb.WriteString("\n[::b]Data:[::-]\n")
+	for _, key := range sortedKeys(configMap.Data) {
+		sb.WriteString(fmt.Sprintf("[yellow]%s[-]:\n%s\n\n", tview.Escape(key), tview.Escape(configMap.Data[key])))
+	}
+	for _, key := range sortedKeys(configMap.BinaryData) {
+		sb.WriteString(fmt.Sprintf("[yellow]%s[-]: [::d]%d bytes of binary data[::-]\n", tview.Escape(key), len(configMap.BinaryData[key])))
+	}
+	return sb.String()
+}
+
+// formatSecretDetails lists the keys of a secret. Values are only decoded
+// when reveal is set, which the user asks for explicitly.
+func formatSecretDetails(secret *v1.Secret, reveal bool) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]Secret Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", secret.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", secret.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(secret.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Type: [yellow]%s[-]\n", secret.Type))
+
+	sb.WriteString("\n[::b]Data:[::-]\n")
+	for _, key := range sortedKeys(secret.Data) {
+		if reveal {
+			sb.WriteString(fmt.Sprintf("[yellow]%s[-]:\n%s\n\n", tview.Escape(key), tview.Escape(string(secret.Data[key]))))
+		} else {
+			sb.WriteString(fmt.Sprintf("[yellow]%s[-]: [::d]%d bytes[::-]\n", tview.Escape(key), len(secret.Data[key])))
+		}
+	}
+
+	if !reveal {
+		sb.WriteString("\nPress 'D' (Shift+d) to reveal the decoded values.\n")
+	}
+	return sb.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
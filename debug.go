@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// defaultDebugImage is offered when starting an ephemeral debug container.
+const defaultDebugImage = "busybox:1.36"
+
+// debugContainerTimeout is how long to wait for the debug container to start.
+const debugContainerTimeout = 2 * time.Minute
+
+// startDebugContainer adds an ephemeral container running image to the pod,
+// sharing the process namespace of targetContainer, like kubectl debug does,
+// and attaches to it once it runs.
+func (state *AppState) startDebugContainer(podName, podNamespace, targetContainer, image string) {
+	state.resetOutput(podName + "-debug")
+	state.secondSection.SetText(fmt.Sprintf("Starting a debug container with image '%s' in pod '%s'...", image, podName))
+
+	go func() {
+		name, err := state.addDebugContainer(podName, podNamespace, targetContainer, image)
+		if err != nil {
+			state.app.QueueUpdateDraw(func() {
+				state.secondSection.SetText(fmt.Sprintf("[red]Error starting a debug container in pod '%s': %v[-]", podName, err))
+			})
+			return
+		}
+
+		state.app.QueueUpdateDraw(func() {
+			command := state.kubectlCommand("attach", "-it", podName, "--namespace="+podNamespace, "-c", name)
+			if err := state.runInteractiveCommand(command); err != nil {
+				state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
+				return
+			}
+			state.secondSection.SetText(fmt.Sprintf("[green]Debug container '%s' is running in pod '%s'.[-] It stays in the pod until the pod is deleted.", name, podName))
+		})
+	}()
+}
+
+// addDebugContainer creates the ephemeral container and waits for it to run,
+// returning its name.
+func (state *AppState) addDebugContainer(podName, podNamespace, targetContainer, image string) (string, error) {
+	pods := state.clientset.CoreV1().Pods(podNamespace)
+	pod, err := pods.Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	name := "debugger-" + utilrand.String(5)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		},
+		TargetContainerName: targetContainer,
+	})
+	if _, err := pods.UpdateEphemeralContainers(context.TODO(), podName, pod, metav1.UpdateOptions{}); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), debugContainerTimeout)
+	defer cancel()
+	err = wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			if status.State.Terminated != nil {
+				return false, fmt.Errorf("debug container exited: %s", containerStateText(status.State))
+			}
+			return status.State.Running != nil, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("waiting for debug container '%s': %v", name, err)
+	}
+	return name, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// showPodDescription renders a kubectl describe style view of pod without
+// shelling out, followed by the pod's events once they're fetched.
+func (state *AppState) showPodDescription(pod *v1.Pod) {
+	description := state.formatPodDescription(pod)
+	state.secondSection.SetText(description + "\n[::b]Events:[::-]\nLoading…\n")
+	state.secondSection.ScrollToBeginning()
+
+	label := state.outputLabel
+	cs := state.clientset
+	go func() {
+		events, err := cs.CoreV1().Events(pod.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: podEventsSelector(pod)})
+
+		var sb strings.Builder
+		sb.WriteString(description)
+		sb.WriteString("\n[::b]Events:[::-]\n")
+		switch {
+		case err != nil:
+			sb.WriteString(fmt.Sprintf("%sError fetching events: %v[-]\n", colorTag(state.theme.error), err))
+		case len(events.Items) == 0:
+			sb.WriteString("<none>\n")
+		default:
+			sb.WriteString(formatEvents(events.Items))
+		}
+
+		state.app.QueueUpdateDraw(func() {
+			// Leave the output alone if something else replaced it meanwhile
+			if state.outputLabel != label {
+				return
+			}
+			row, column := state.secondSection.GetScrollOffset()
+			state.secondSection.SetText(sb.String())
+			state.secondSection.ScrollTo(row, column)
+		})
+	}()
+}
+
+func (state *AppState) formatPodDescription(pod *v1.Pod) string {
+	var sb strings.Builder
+	field := func(name, value string) {
+		sb.WriteString(fmt.Sprintf("%-16s %s%s[-]\n", name+":", colorTag(state.theme.value), tview.Escape(value)))
+	}
+
+	field("Name", pod.Name)
+	field("Namespace", pod.Namespace)
+	field("Node", orNone(pod.Spec.NodeName))
+	if pod.Status.StartTime != nil {
+		field("Start Time", pod.Status.StartTime.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	}
+	field("Labels", formatMap(pod.Labels))
+	field("Status", string(pod.Status.Phase))
+	if pod.Status.Reason != "" {
+		field("Reason", pod.Status.Reason)
+	}
+	field("IP", orNone(pod.Status.PodIP))
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		field("Controlled By", owner.Kind+"/"+owner.Name)
+	}
+	field("QoS Class", string(pod.Status.QOSClass))
+
+	statuses := make(map[string]v1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+	sb.WriteString("\n[::b]Containers:[::-]\n")
+	for _, container := range pod.Spec.Containers {
+		sb.WriteString(fmt.Sprintf("  [::b]%s[::-]\n", tview.Escape(container.Name)))
+		sb.WriteString(fmt.Sprintf("    Image:         %s\n", tview.Escape(container.Image)))
+		if len(container.Ports) > 0 {
+			var ports []string
+			for _, port := range container.Ports {
+				ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol))
+			}
+			sb.WriteString(fmt.Sprintf("    Ports:         %s\n", strings.Join(ports, ", ")))
+		}
+		status, ok := statuses[container.Name]
+		if !ok {
+			sb.WriteString("    State:         Unknown\n")
+			continue
+		}
+		stateText := tview.Escape(containerStateText(status.State))
+		if status.State.Running != nil {
+			stateText += " since " + status.State.Running.StartedAt.Format("2006-01-02 15:04:05")
+		}
+		sb.WriteString(fmt.Sprintf("    State:         %s\n", stateText))
+		if status.LastTerminationState.Terminated != nil {
+			sb.WriteString(fmt.Sprintf("    Last State:    %s\n", tview.Escape(containerStateText(status.LastTerminationState))))
+		}
+		sb.WriteString(fmt.Sprintf("    Ready:         %t\n", status.Ready))
+		restarts := fmt.Sprintf("%d", status.RestartCount)
+		if status.RestartCount > 0 {
+			restarts = colorTag(state.theme.error) + restarts + "[-]"
+		}
+		sb.WriteString(fmt.Sprintf("    Restart Count: %s\n", restarts))
+	}
+
+	sb.WriteString("\n[::b]Conditions:[::-]\n")
+	if len(pod.Status.Conditions) == 0 {
+		sb.WriteString("  <none>\n")
+	}
+	for _, condition := range pod.Status.Conditions {
+		line := fmt.Sprintf("  %-26s %s", condition.Type, condition.Status)
+		if condition.Status != v1.ConditionTrue && condition.Message != "" {
+			line += " (" + tview.Escape(condition.Message) + ")"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n[::b]Volumes:[::-]\n")
+	if len(pod.Spec.Volumes) == 0 {
+		sb.WriteString("  <none>\n")
+	}
+	for _, volume := range pod.Spec.Volumes {
+		sb.WriteString(fmt.Sprintf("  %-26s %s\n", tview.Escape(volume.Name), volumeSource(volume)))
+	}
+	return sb.String()
+}
+
+// volumeSource names the kind of a volume and what it points at.
+func volumeSource(volume v1.Volume) string {
+	switch {
+	case volume.ConfigMap != nil:
+		return "ConfigMap " + volume.ConfigMap.Name
+	case volume.Secret != nil:
+		return "Secret " + volume.Secret.SecretName
+	case volume.PersistentVolumeClaim != nil:
+		return "PersistentVolumeClaim " + volume.PersistentVolumeClaim.ClaimName
+	case volume.EmptyDir != nil:
+		return "EmptyDir"
+	case volume.HostPath != nil:
+		return "HostPath " + volume.HostPath.Path
+	case volume.Projected != nil:
+		return "Projected"
+	case volume.DownwardAPI != nil:
+		return "DownwardAPI"
+	default:
+		return "Other"
+	}
+}
+
+func formatMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		pairs = append(pairs, key+"="+m[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
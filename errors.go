@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// apiBackoff spaces out retries of transient API errors: 4 attempts over
+// roughly 1.5 seconds, so a flaky connection doesn't hang the UI.
+var apiBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// describeAPIError turns an API error into a message that says whether the
+// problem is permissions or connectivity, which the raw error often hides.
+func describeAPIError(err error) string {
+	switch {
+	case apierrors.IsForbidden(err):
+		return fmt.Sprintf("Forbidden: your user is not allowed to do this (check its RBAC roles): %v", err)
+	case apierrors.IsUnauthorized(err):
+		return fmt.Sprintf("Unauthorized: the cluster rejected your credentials: %v", err)
+	case errors.Is(err, context.Canceled):
+		return err.Error()
+	case isCertificateError(err):
+		return fmt.Sprintf("Untrusted: the API server's certificate could not be verified: %v", err)
+	case isNetworkError(err), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsServiceUnavailable(err):
+		return fmt.Sprintf("Unreachable: could not reach the API server: %v", err)
+	}
+	return err.Error()
+}
+
+// withRetry runs fn, retrying it with apiBackoff while it fails with a
+// transient error, until ctx is done.
+func withRetry(ctx context.Context, fn func() error) error {
+	return retry.OnError(apiBackoff, func(err error) bool {
+		return ctx.Err() == nil && isTransientError(err)
+	}, fn)
+}
+
+// isTransientError reports whether an API call is worth retrying. Errors
+// like NotFound or Forbidden will not go away by asking again, and neither
+// will a cancelled call or a certificate that isn't trusted.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || isCertificateError(err) {
+		return false
+	}
+	return apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		isNetworkError(err) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isNetworkError reports whether err is a network timeout or a failure to
+// dial or talk to the server. client-go wraps every transport error in a
+// *url.Error, which is a net.Error too, so that alone says nothing.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// isCertificateError reports whether err comes from verifying the server's
+// TLS certificate.
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &invalidErr) ||
+		errors.As(err, &hostnameErr)
+}
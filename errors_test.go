@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransientError(t *testing.T) {
+	urlErr := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://cluster/api/v1/pods", Err: err}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"cancelled", urlErr(context.Canceled), false},
+		{"deadline", urlErr(context.DeadlineExceeded), false},
+		{"unknown authority", urlErr(x509.UnknownAuthorityError{}), false},
+		{"dial", urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), true},
+		{"not found", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web"), false},
+		{"service unavailable", apierrors.NewServiceUnavailable("overloaded"), true},
+	}
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("isTransientError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// showPodEvents lists the events recorded for a pod, newest first.
+func (state *AppState) showPodEvents(pod *v1.Pod) {
+	label := pod.Name + "-events"
+	state.resetOutput(label)
+	state.secondSection.SetText(fmt.Sprintf("Loading events for pod '%s'…", pod.Name))
+
+	cs := state.clientset
+	go func() {
+		events, err := cs.CoreV1().Events(pod.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: podEventsSelector(pod)})
+		state.app.QueueUpdateDraw(func() {
+			if state.outputLabel != label {
+				return
+			}
+			switch {
+			case err != nil:
+				state.secondSection.SetText(fmt.Sprintf("[red]Error fetching events for pod '%s': %v[-]", pod.Name, err))
+			case len(events.Items) == 0:
+				state.secondSection.SetText(fmt.Sprintf("No events found for pod '%s'.", pod.Name))
+			default:
+				state.secondSection.SetText(formatEvents(events.Items))
+			}
+		})
+	}()
+}
+
+// podEventsSelector selects the events of pod itself, leaving out those of a
+// ReplicaSet, Service or claim with the same name, and those of an earlier
+// pod that had it.
+func podEventsSelector(pod *v1.Pod) string {
+	return fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("involvedObject.name", pod.Name),
+		fields.OneTermEqualSelector("involvedObject.uid", string(pod.UID)),
+	).String()
+}
+
+func formatEvents(events []v1.Event) string {
+	sort.Slice(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
+	})
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[::b]%-10s %-8s %-22s %-6s %s[::-]\n", "LAST SEEN", "TYPE", "REASON", "COUNT", "MESSAGE"))
+	for _, event := range events {
+		line := fmt.Sprintf("%-10s %-8s %-22s %-6d %s", formatAge(eventTime(event)), event.Type, event.Reason, eventCount(event), tview.Escape(strings.TrimSpace(event.Message)))
+		if event.Type == v1.EventTypeWarning {
+			line = "[red]" + line + "[-]"
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// eventTime returns when an event was last observed. Events written through
+// the events.k8s.io API only set EventTime or the series.
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+func eventCount(event v1.Event) int32 {
+	if event.Series != nil {
+		return event.Series.Count
+	}
+	if event.Count == 0 {
+		return 1
+	}
+	return event.Count
+}
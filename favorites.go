@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// namespaceDivider separates the favorite namespaces from the rest in the
+// namespace dropdown.
+const namespaceDivider = "──────────"
+
+// namespaceDropdownOptions lists the dropdown entries: the fixed choices, then
+// the favorite namespaces that exist in this context, then the others.
+func (state *AppState) namespaceDropdownOptions(namespaceNames []string) []string {
+	options := []string{"Select a namespace", "all"}
+	var others []string
+	for _, name := range namespaceNames {
+		if slices.Contains(state.config.FavoriteNamespaces, name) {
+			options = append(options, name)
+		} else {
+			others = append(others, name)
+		}
+	}
+	if len(options) > 2 && len(others) > 0 {
+		options = append(options, namespaceDivider)
+	}
+	return append(options, others...)
+}
+
+// highlightedNamespace returns the namespace of the highlighted tree node,
+// falling back to the one picked in the dropdown.
+func (state *AppState) highlightedNamespace() string {
+	if node := state.treeView.GetCurrentNode(); node != nil {
+		if obj, ok := node.GetReference().(metav1.Object); ok {
+			return obj.GetNamespace()
+		}
+		if parent := findParentNode(state.treeView.GetRoot(), node); parent == state.treeView.GetRoot() {
+			return node.GetText()
+		}
+	}
+	if slices.Contains(state.namespaceNames, state.selectedNamespace) {
+		return state.selectedNamespace
+	}
+	return ""
+}
+
+// toggleFavoriteNamespace adds the highlighted namespace to the favorites, or
+// removes it, saves the config file and rebuilds the dropdown.
+func (state *AppState) toggleFavoriteNamespace() {
+	namespace := state.highlightedNamespace()
+	if namespace == "" {
+		state.setStatus("Highlight a namespace to mark it as a favorite", statusWarning)
+		return
+	}
+
+	favorites := state.config.FavoriteNamespaces
+	message := fmt.Sprintf("Added '%s' to the favorite namespaces", namespace)
+	if i := slices.Index(favorites, namespace); i >= 0 {
+		favorites = slices.Delete(favorites, i, i+1)
+		message = fmt.Sprintf("Removed '%s' from the favorite namespaces", namespace)
+	} else {
+		favorites = append(favorites, namespace)
+	}
+	state.config.FavoriteNamespaces = favorites
+
+	if err := saveConfig(configPath(), state.config); err != nil {
+		state.setStatus(fmt.Sprintf("Error saving favorites: %v", err), statusError)
+	} else {
+		state.setStatus(message, statusSuccess)
+	}
+
+	// Rebuild the options without reloading the tree for the same selection
+	state.namespaceOptions = state.namespaceDropdownOptions(state.namespaceNames)
+	state.namespaceDropdown.SetOptions(state.namespaceOptions, nil)
+	if i := slices.Index(state.namespaceOptions, state.selectedNamespace); i >= 0 {
+		state.namespaceDropdown.SetCurrentOption(i)
+	}
+	state.namespaceDropdown.SetSelectedFunc(state.namespaceSelectHandler)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Bonuses and penalties of fuzzyScore. Matches in a row and at the start of
+// a name segment count most, so "apisrv" ranks api-server-7d9f above
+// app-init-sidecar-rv.
+const (
+	fuzzyMatchScore       = 10
+	fuzzyConsecutiveBonus = 15
+	fuzzySegmentBonus     = 20
+	fuzzyGapPenalty       = 1
+)
+
+// fuzzyScore reports whether the characters of pattern appear in name in
+// order, ignoring case, and how well they match: the higher the better.
+func fuzzyScore(pattern, name string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	name = strings.ToLower(name)
+
+	score := 0
+	end := -1 // where the previous match ended in name
+	p := 0
+	for i, r := range name {
+		if p == len(pattern) {
+			break
+		}
+		want, size := utf8.DecodeRuneInString(pattern[p:])
+		if r != want {
+			continue
+		}
+		score += fuzzyMatchScore
+		switch {
+		case i == end:
+			score += fuzzyConsecutiveBonus
+		case i == 0 || strings.ContainsRune("-._", rune(name[i-1])):
+			score += fuzzySegmentBonus
+		}
+		if end >= 0 {
+			score -= (i - end) * fuzzyGapPenalty
+		}
+		end = i + utf8.RuneLen(r)
+		p += size
+	}
+	if p < len(pattern) {
+		return 0, false
+	}
+	return score, true
+}
+
+// sortByFuzzyScore orders pods by how well their name matches pattern, best
+// first, keeping name order among equal scores.
+func sortByFuzzyScore(pattern string, pods []v1.Pod) {
+	scores := make(map[string]int, len(pods))
+	for _, pod := range pods {
+		scores[pod.Name], _ = fuzzyScore(pattern, pod.Name)
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		return scores[pods[i].Name] > scores[pods[j].Name]
+	})
+}
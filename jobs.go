@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxCronJobChildren caps how many recent jobs are listed under a CronJob.
+const maxCronJobChildren = 10
+
+// newJobNode creates the tree node for a job, showing its succeeded and
+// expected completions. Selecting it expands the job's pods, so the usual
+// pod keys (logs, describe, ...) work on them.
+func (state *AppState) newJobNode(job batchv1.Job) *tview.TreeNode {
+	text := fmt.Sprintf("%s %d/%d [::d]%s[::-]", job.Name, job.Status.Succeeded, jobCompletions(&job), formatAge(job.CreationTimestamp.Time))
+	node := tview.NewTreeNode(text).SetReference(&job).SetColor(state.jobStatusColor(&job))
+	node.SetExpanded(false)
+	node.SetSelectedFunc(func() {
+		if node.IsExpanded() {
+			node.SetExpanded(false)
+			return
+		}
+		state.loadSelectorPods(node, job.Namespace, job.Spec.Selector)
+		node.SetExpanded(true)
+	})
+	return node
+}
+
+// newCronJobNode creates the tree node for a cronjob. Selecting it expands
+// the most recent jobs it created.
+func (state *AppState) newCronJobNode(cronJob batchv1.CronJob) *tview.TreeNode {
+	color := state.theme.success
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		color = state.theme.muted
+	}
+	text := fmt.Sprintf("%s %s [::d]%s[::-]", cronJob.Name, cronJob.Spec.Schedule, formatAge(cronJob.CreationTimestamp.Time))
+	node := tview.NewTreeNode(text).SetReference(&cronJob).SetColor(color)
+	node.SetExpanded(false)
+	node.SetSelectedFunc(func() {
+		if node.IsExpanded() {
+			node.SetExpanded(false)
+			return
+		}
+		state.loadCronJobChildren(node, &cronJob)
+		node.SetExpanded(true)
+	})
+	return node
+}
+
+// loadCronJobChildren replaces node's children with the most recent jobs
+// owned by cronJob, newest first.
+func (state *AppState) loadCronJobChildren(node *tview.TreeNode, cronJob *batchv1.CronJob) {
+	state.loadChildren(node, func(ctx context.Context) []*tview.TreeNode {
+		jobs, err := state.cronJobChildren(ctx, cronJob)
+		if err != nil {
+			return []*tview.TreeNode{tview.NewTreeNode(fmt.Sprintf("Error listing jobs: %v", err)).SetColor(state.theme.error)}
+		}
+		if len(jobs) == 0 {
+			return []*tview.TreeNode{tview.NewTreeNode("No jobs").SetColor(state.theme.muted)}
+		}
+		children := make([]*tview.TreeNode, 0, len(jobs))
+		for _, job := range jobs {
+			children = append(children, state.newJobNode(job))
+		}
+		return children
+	})
+}
+
+// cronJobChildren lists the jobs of cronJob's namespace and keeps the ones it
+// owns. It runs off the UI goroutine.
+func (state *AppState) cronJobChildren(ctx context.Context, cronJob *batchv1.CronJob) ([]batchv1.Job, error) {
+	state.mu.Lock()
+	cs := state.clientset
+	state.mu.Unlock()
+
+	list, err := cs.BatchV1().Jobs(cronJob.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []batchv1.Job
+	for _, job := range list.Items {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.UID == cronJob.UID {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.After(jobs[j].CreationTimestamp.Time)
+	})
+	if len(jobs) > maxCronJobChildren {
+		jobs = jobs[:maxCronJobChildren]
+	}
+	return jobs, nil
+}
+
+func jobCompletions(job *batchv1.Job) int32 {
+	if job.Spec.Completions != nil {
+		return *job.Spec.Completions
+	}
+	return 1
+}
+
+// jobStatus summarizes a job from its conditions.
+func jobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		case batchv1.JobSuspended:
+			return "Suspended"
+		}
+	}
+	return "Running"
+}
+
+func (state *AppState) jobStatusColor(job *batchv1.Job) tcell.Color {
+	switch jobStatus(job) {
+	case "Complete":
+		return state.theme.success
+	case "Failed":
+		return state.theme.error
+	case "Suspended":
+		return state.theme.muted
+	}
+	return state.theme.warning
+}
+
+func formatJobDetails(job *batchv1.Job) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]Job Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", job.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", job.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(job.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Status: [yellow]%s[-]\n", jobStatus(job)))
+	if job.Status.StartTime != nil {
+		sb.WriteString(fmt.Sprintf("Started: [yellow]%s[-]\n", job.Status.StartTime.Format("2006-01-02 15:04:05")))
+	}
+	if job.Status.CompletionTime != nil {
+		sb.WriteString(fmt.Sprintf("Completed: [yellow]%s[-]\n", job.Status.CompletionTime.Format("2006-01-02 15:04:05")))
+	}
+
+	sb.WriteString("\n[::b]Completions:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Desired: [yellow]%d[-]\n", jobCompletions(job)))
+	sb.WriteString(fmt.Sprintf("Succeeded: [yellow]%d[-]\n", job.Status.Succeeded))
+	sb.WriteString(fmt.Sprintf("Failed: [yellow]%d[-]\n", job.Status.Failed))
+	sb.WriteString(fmt.Sprintf("Active: [yellow]%d[-]\n", job.Status.Active))
+	if job.Spec.BackoffLimit != nil {
+		sb.WriteString(fmt.Sprintf("Backoff Limit: [yellow]%d[-]\n", *job.Spec.BackoffLimit))
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status == v1.ConditionTrue && condition.Message != "" {
+			sb.WriteString(fmt.Sprintf("\n%s: %s\n", condition.Type, condition.Message))
+		}
+	}
+
+	sb.WriteString("\nPress Enter on the job to list its pods, then use the pod keys to view their logs.\n")
+	return sb.String()
+}
+
+func formatCronJobDetails(cronJob *batchv1.CronJob) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]CronJob Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", cronJob.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", cronJob.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(cronJob.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Schedule: [yellow]%s[-]\n", cronJob.Spec.Schedule))
+	if cronJob.Spec.TimeZone != nil {
+		sb.WriteString(fmt.Sprintf("Time Zone: [yellow]%s[-]\n", *cronJob.Spec.TimeZone))
+	}
+	suspended := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+	sb.WriteString(fmt.Sprintf("Suspended: [yellow]%t[-]\n", suspended))
+	sb.WriteString(fmt.Sprintf("Concurrency Policy: [yellow]%s[-]\n", cronJob.Spec.ConcurrencyPolicy))
+
+	lastSchedule := "Never"
+	if cronJob.Status.LastScheduleTime != nil {
+		lastSchedule = fmt.Sprintf("%s (%s ago)", cronJob.Status.LastScheduleTime.Format("2006-01-02 15:04:05"), formatAge(cronJob.Status.LastScheduleTime.Time))
+	}
+	sb.WriteString(fmt.Sprintf("Last Schedule: [yellow]%s[-]\n", lastSchedule))
+	if cronJob.Status.LastSuccessfulTime != nil {
+		sb.WriteString(fmt.Sprintf("Last Successful: [yellow]%s[-]\n", cronJob.Status.LastSuccessfulTime.Format("2006-01-02 15:04:05")))
+	}
+	sb.WriteString(fmt.Sprintf("Active Jobs: [yellow]%d[-]\n", len(cronJob.Status.Active)))
+
+	sb.WriteString("\nPress Enter on the cronjob to list its recent jobs.\n")
+	return sb.String()
+}
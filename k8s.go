@@ -3,17 +3,22 @@ package main
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"golang.org/x/sync/errgroup"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -22,54 +27,80 @@ import (
 
 func (state *AppState) loadContexts() {
 	go func() {
-		kubeconfigPath := *state.kubeconfig
-		config, err := clientcmd.LoadFromFile(kubeconfigPath)
+		loadingRules := state.kubeconfigLoadingRules()
+		config, err := loadingRules.Load()
 		if err != nil {
-			// Handle error
+			state.showKubeconfigError(fmt.Errorf("could not load kubeconfig %s: %v", kubeconfigSource(loadingRules), err))
+			return
+		}
+		if len(config.Contexts) == 0 {
+			state.showKubeconfigError(fmt.Errorf("kubeconfig %s does not define any contexts", kubeconfigSource(loadingRules)))
 			return
 		}
 
 		var contexts []string
-		for contextName := range config.Contexts {
+		origins := make(map[string]string, len(config.Contexts))
+		for contextName, kubeContext := range config.Contexts {
 			contexts = append(contexts, contextName)
+			origins[contextName] = kubeContext.LocationOfOrigin
 		}
 		sort.Strings(contexts)
 		state.selectedContext = config.CurrentContext
+		// Go back to the context of the previous session, if it still
+		// exists, unless --context picks one
+		if _, ok := config.Contexts[state.savedState.Context]; ok {
+			state.selectedContext = state.savedState.Context
+		}
+		if *state.startContext != "" {
+			if _, ok := config.Contexts[*state.startContext]; !ok {
+				state.showKubeconfigError(fmt.Errorf("context %s given with --context is not in kubeconfig %s, expected one of: %s",
+					*state.startContext, kubeconfigSource(loadingRules), strings.Join(contexts, ", ")))
+				return
+			}
+			state.selectedContext = *state.startContext
+		}
+		if *state.startNamespace == "" && state.selectedContext == state.savedState.Context {
+			*state.startNamespace = state.savedState.Namespace
+		}
 
 		state.app.QueueUpdateDraw(func() {
 			state.contextOptions = contexts
-			state.contextDropdown.SetOptions(contexts, state.contextSelectHandler)
-			state.contextDropdown.SetCurrentOption(state.getIndexOfCurrentContext(contexts, state.selectedContext))
+			state.contextOrigins = origins
+			// The clients for this context are built below, so don't switch to it
+			state.contextDropdown.SetOptions(contexts, nil)
+			state.selectContextOption(state.selectedContext)
 			state.contextDropdown.SetDisabled(false)
+			state.updateStatusBar()
 		})
 
 		// Initialize Kubernetes clients
 		configOverrides := &clientcmd.ConfigOverrides{CurrentContext: state.selectedContext}
-		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
-			configOverrides,
-		)
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
 		restConfig, err := clientConfig.ClientConfig()
 		if err != nil {
-			// Handle error
+			state.showKubeconfigError(fmt.Errorf("invalid configuration for context %s: %v", state.selectedContext, err))
 			return
 		}
 
 		cs, err := kubernetes.NewForConfig(restConfig)
 		if err != nil {
-			// Handle error
+			state.showKubeconfigError(fmt.Errorf("could not create a Kubernetes client for context %s: %v", state.selectedContext, err))
 			return
 		}
 
 		dc, err := dynamic.NewForConfig(restConfig)
 		if err != nil {
-			// Handle error
+			state.showKubeconfigError(fmt.Errorf("could not create a Kubernetes client for context %s: %v", state.selectedContext, err))
 			return
 		}
 
 		mc, err := metrics.NewForConfig(restConfig)
 		if err != nil {
-			// Handle error
+			state.showKubeconfigError(fmt.Errorf("could not create a metrics client for context %s: %v", state.selectedContext, err))
+			return
+		}
+		if !state.metricsAPIServed(cs) {
+			mc = nil
 		}
 
 		state.mu.Lock()
@@ -77,6 +108,11 @@ func (state *AppState) loadContexts() {
 		state.dynamicClient = dc
 		state.metricsClient = mc
 		state.mu.Unlock()
+		state.logger.Info("created the Kubernetes clients", "context", state.selectedContext, "server", restConfig.Host)
+		state.app.QueueUpdateDraw(func() {
+			state.serverHost = restConfig.Host
+			state.updateStatusBar()
+		})
 
 		// Signal that the clients are ready
 		select {
@@ -85,20 +121,61 @@ func (state *AppState) loadContexts() {
 			close(state.k8sClientsReady)
 		}
 
+		state.discoverPrometheus(cs, restConfig)
+		state.app.QueueUpdateDraw(state.updateHelperText)
+
 		// Load namespaces now that clients are ready
 		state.loadNamespaces()
 	}()
 }
 
-func (state *AppState) loadNamespaces() {
+// kubeconfigLoadingRules finds the kubeconfig the way kubectl does:
+// --kubeconfig wins, otherwise the files listed in $KUBECONFIG are merged,
+// falling back to ~/.kube/config.
+func (state *AppState) kubeconfigLoadingRules() *clientcmd.ClientConfigLoadingRules {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *state.kubeconfig
+	return loadingRules
+}
+
+// kubeconfigSource describes where the kubeconfig was loaded from, for error
+// messages.
+func kubeconfigSource(loadingRules *clientcmd.ClientConfigLoadingRules) string {
+	if loadingRules.ExplicitPath != "" {
+		return loadingRules.ExplicitPath
+	}
+	return strings.Join(loadingRules.GetLoadingPrecedence(), string(filepath.ListSeparator))
+}
+
+// showKubeconfigError explains why the clients could not be set up, instead
+// of leaving the UI stuck on "Loading contexts...".
+func (state *AppState) showKubeconfigError(err error) {
+	state.logger.Error("connecting to the cluster", "err", err)
+	state.app.QueueUpdateDraw(func() {
+		if len(state.contextOptions) == 0 {
+			state.contextDropdown.SetOptions([]string{"No context loaded"}, nil)
+		}
+		state.secondSection.SetText(fmt.Sprintf("[red]Error: %v[-]\n\nCheck that the kubeconfig exists and is valid, or point Podminator at another one with --kubeconfig.", err))
+		rootNode := tview.NewTreeNode("Could not connect to the cluster").SetColor(state.theme.error)
+		state.treeView.SetRoot(rootNode).SetCurrentNode(rootNode)
+	})
+}
+
+func (state *AppState) loadNamespaces() error {
 	state.mu.Lock()
 	cs := state.clientset
 	state.mu.Unlock()
 
-	namespaceList, err := cs.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	var namespaceList *v1.NamespaceList
+	err := withRetry(context.TODO(), func() (err error) {
+		namespaceList, err = cs.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		return err
+	})
 	if err != nil {
-		// Handle error
-		return
+		state.app.QueueUpdateDraw(func() {
+			state.secondSection.SetText(fmt.Sprintf("[red]Error listing namespaces: %s[-]", tview.Escape(describeAPIError(err))))
+		})
+		return err
 	}
 
 	var namespaceNames []string
@@ -106,28 +183,54 @@ func (state *AppState) loadNamespaces() {
 		namespaceNames = append(namespaceNames, ns.Name)
 	}
 	sort.Strings(namespaceNames)
-	newNamespaceOptions := append([]string{"Select a namespace", "all"}, namespaceNames...)
+
+	// --namespace only picks the namespace at startup, not after a context switch
+	startNamespace := *state.startNamespace
+	*state.startNamespace = ""
 
 	state.app.QueueUpdateDraw(func() {
-		state.namespaceOptions = newNamespaceOptions
+		state.namespaceNames = namespaceNames
+		state.namespaceOptions = state.namespaceDropdownOptions(namespaceNames)
+		startIndex := 0
+		if startNamespace != "" {
+			for i, option := range state.namespaceOptions[1:] {
+				if option == startNamespace {
+					startIndex = i + 1
+					break
+				}
+			}
+		}
 		state.namespaceDropdown.SetOptions(state.namespaceOptions, state.namespaceSelectHandler)
-		state.namespaceDropdown.SetCurrentOption(0)
+		state.namespaceDropdown.SetCurrentOption(startIndex)
 		state.namespaceDropdown.SetDisabled(false)
+		if startNamespace != "" && startIndex == 0 {
+			state.secondSection.SetText(fmt.Sprintf("[red]Namespace '%s' not found in this context, pick one from the dropdown.[-]", startNamespace))
+		}
 	})
+	return nil
 }
 
 func (state *AppState) namespaceSelectHandler(option string, index int) {
+	if option == namespaceDivider {
+		// The divider isn't a namespace, go back to the previous selection
+		if i := slices.Index(state.namespaceOptions, state.selectedNamespace); i >= 0 {
+			state.namespaceDropdown.SetCurrentOption(i)
+		}
+		return
+	}
 	state.selectedNamespace = option
 	state.searchInput.SetText("")
+	state.updateStatusBar()
 	if state.selectedNamespace == "Select a namespace" {
-		rootNode := tview.NewTreeNode("Please select a namespace to load pods").SetColor(tcell.ColorYellow)
+		state.stopPodWatch()
+		rootNode := tview.NewTreeNode("Please select a namespace to load pods").SetColor(state.theme.warning)
 		state.treeView.SetRoot(rootNode).SetCurrentNode(rootNode)
 		state.secondSection.SetText("Output will be displayed here")
 	} else {
 		err := state.updatePodTreeView("")
-		if err != nil {
-			// Handle error
-		}
+		state.finishRefresh(err)
+		state.startPodWatch()
+		state.rememberSelection()
 		state.treeView.SetCurrentNode(state.treeView.GetRoot())
 		state.setFocusHighlight(state.treeView)
 		if state.selectedNamespace == "all" {
@@ -138,49 +241,147 @@ func (state *AppState) namespaceSelectHandler(option string, index int) {
 	}
 }
 
+// rememberSelection saves the current context and namespace, so the next
+// session starts there.
+func (state *AppState) rememberSelection() {
+	state.savedState = SavedState{Context: state.selectedContext}
+	if state.selectedNamespace == "all" || slices.Contains(state.namespaceNames, state.selectedNamespace) {
+		state.savedState.Namespace = state.selectedNamespace
+	}
+	if err := saveState(statePath(), state.savedState); err != nil {
+		state.logger.Warn("saving the selection", "path", statePath(), "err", err)
+		state.setStatus(fmt.Sprintf("Could not save the selected namespace: %v", err), statusWarning)
+	}
+}
+
 func (state *AppState) contextSelectHandler(option string, index int) {
+	previousContext := state.selectedContext
 	state.selectedContext = option
+	// Keep the tree layout of the context being left, to restore it when
+	// coming back
+	if root := state.treeView.GetRoot(); root != nil {
+		state.recordExpansionState(root)
+	}
+	state.contextExpansionStates[previousContext] = state.namespaceExpansionState
+	state.contextDropdown.SetDisabled(true)
+	state.namespaceDropdown.SetDisabled(true)
+	state.setStatus(fmt.Sprintf("Switching context to %s…", option), statusInfo)
 	go func() {
-		rawConfig, err := clientcmd.LoadFromFile(*state.kubeconfig)
-		if err != nil {
-			// Handle error
-			return
-		}
-		rawConfig.CurrentContext = state.selectedContext
-		config, err := clientcmd.NewDefaultClientConfig(*rawConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
-		if err != nil {
-			// Handle error
-			return
-		}
-		cs, err := kubernetes.NewForConfig(config)
-		if err != nil {
-			// Handle error
-			return
-		}
-		dc, err := dynamic.NewForConfig(config)
-		if err != nil {
-			// Handle error
+		if err := state.switchContext(option); err != nil {
+			// The old clients are still in use, so the dropdown must say so
+			state.app.QueueUpdateDraw(func() {
+				state.selectedContext = previousContext
+				state.selectContextOption(previousContext)
+				state.contextDropdown.SetDisabled(false)
+				state.namespaceDropdown.SetDisabled(false)
+				state.secondSection.SetText(fmt.Sprintf("[red]Error switching to context %s: %s[-]\n\nStill connected to %s.", tview.Escape(option), tview.Escape(err.Error()), tview.Escape(previousContext)))
+				state.setStatus(fmt.Sprintf("Could not switch to context %s, still on %s", option, previousContext), statusError)
+			})
 			return
 		}
-		mc, err := metrics.NewForConfig(config)
-		if err != nil {
-			// Handle error
-		}
 
-		state.mu.Lock()
-		state.clientset = cs
-		state.dynamicClient = dc
-		state.metricsClient = mc
-		state.mu.Unlock()
-
-		state.loadNamespaces()
-		state.namespaceExpansionState = make(map[string]bool)
+		err := state.loadNamespaces()
 		state.app.QueueUpdateDraw(func() {
+			state.contextDropdown.SetDisabled(false)
+			if expansionState, ok := state.contextExpansionStates[option]; ok {
+				state.namespaceExpansionState = expansionState
+			} else {
+				state.namespaceExpansionState = make(map[string]bool)
+			}
+			if err != nil {
+				// Nothing of the previous context may stay on screen: its
+				// tree goes, and the dropdown stays usable to retry
+				state.stopPodWatch()
+				state.treeView.SetRoot(nil)
+				state.applyTreeNodes(nil, err)
+				state.namespaceDropdown.SetDisabled(false)
+				state.setStatus(fmt.Sprintf("Switched to context %s, but listing its namespaces failed", option), statusError)
+				return
+			}
 			state.namespaceDropdown.SetCurrentOption(0)
+			state.rememberSelection()
+			state.setStatus(fmt.Sprintf("Switched to context %s", option), statusSuccess)
 		})
 	}()
 }
 
+// metricsGroupVersion is the API served by metrics-server.
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+// metricsAPIServed asks the API server, once per context, whether it serves
+// the metrics API. Without it, usage is left out rather than failing on
+// every pod selected. Only a NotFound answer turns usage off: when discovery
+// fails for another reason, the metrics client is kept.
+func (state *AppState) metricsAPIServed(cs kubernetes.Interface) bool {
+	_, err := cs.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion)
+	switch {
+	case err == nil:
+		return true
+	case errors.IsNotFound(err):
+		state.logger.Info("the metrics API is not served, CPU and memory usage are off", "groupVersion", metricsGroupVersion)
+		return false
+	default:
+		state.logger.Warn("could not check whether the metrics API is served", "groupVersion", metricsGroupVersion, "err", err)
+		return true
+	}
+}
+
+// metricsAvailable reports whether the current cluster serves the metrics
+// API.
+func (state *AppState) metricsAvailable() bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.metricsClient != nil
+}
+
+// switchContext builds the clients for the named context and swaps them in.
+func (state *AppState) switchContext(name string) error {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		state.kubeconfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: name},
+	).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %v", err)
+	}
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("could not create a Kubernetes client: %v", err)
+	}
+	dc, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("could not create a Kubernetes client: %v", err)
+	}
+	mc, err := metrics.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("could not create a metrics client: %v", err)
+	}
+	if !state.metricsAPIServed(cs) {
+		mc = nil
+	}
+
+	state.mu.Lock()
+	state.clientset = cs
+	state.dynamicClient = dc
+	state.metricsClient = mc
+	state.mu.Unlock()
+
+	state.discoverPrometheus(cs, config)
+	state.app.QueueUpdateDraw(func() {
+		state.serverHost = config.Host
+		state.updateHelperText()
+		state.updateStatusBar()
+	})
+	return nil
+}
+
+// selectContextOption shows name in the context dropdown without switching
+// to it.
+func (state *AppState) selectContextOption(name string) {
+	state.contextDropdown.SetSelectedFunc(nil)
+	state.contextDropdown.SetCurrentOption(state.getIndexOfCurrentContext(state.contextOptions, name))
+	state.contextDropdown.SetSelectedFunc(state.contextSelectHandler)
+}
+
 func (state *AppState) getIndexOfCurrentContext(contexts []string, currentContext string) int {
 	for i, context := range contexts {
 		if context == currentContext {
@@ -191,7 +392,10 @@ func (state *AppState) getIndexOfCurrentContext(contexts []string, currentContex
 }
 
 func (state *AppState) periodicPodRefresh() {
-	ticker := time.NewTicker(60 * time.Second)
+	if *state.refreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(*state.refreshInterval)
 	defer ticker.Stop()
 
 	for {
@@ -211,57 +415,123 @@ func (state *AppState) periodicPodRefresh() {
 				if selectedNamespaceLocal == "Select a namespace" {
 					return
 				}
-				searchQuery := state.searchInput.GetText()
-				err := state.updatePodTreeView(searchQuery)
-				if err != nil {
-					// Handle error
-					return
-				}
-				state.lastRefreshed = time.Now().Format("15:04:05")
-				state.app.QueueUpdateDraw(func() {
-					state.updateHelperText()
-				})
+				state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
 			}()
 		}
 	}
 }
 
 func (state *AppState) updatePodTreeView(searchQuery string) error {
+	ctx, cancel := state.startRefresh()
+	defer cancel()
+	namespacesWithNodes, err := state.fetchTreeNodes(ctx, searchQuery)
+	if ctx.Err() != nil {
+		// Superseded, the newer refresh fills the tree
+		return nil
+	}
+	return state.applyTreeNodes(namespacesWithNodes, err)
+}
+
+// refreshTreeInBackground is updatePodTreeView for goroutines: it fetches
+// there and only applies the result on the UI goroutine, then calls done,
+// unless a newer refresh superseded it meanwhile.
+func (state *AppState) refreshTreeInBackground(searchQuery string, done func(err error)) {
+	ctx, cancel := state.startRefresh()
+	defer cancel()
+	namespacesWithNodes, err := state.fetchTreeNodes(ctx, searchQuery)
+	if ctx.Err() != nil {
+		return
+	}
+	state.app.QueueUpdateDraw(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		done(state.applyTreeNodes(namespacesWithNodes, err))
+	})
+}
+
+// startRefresh cancels the tree refresh in flight, if any, and returns the
+// context of a new one. A newer refresh, like the next keystroke in the
+// search box, thereby stops the older one's API calls, so they don't stack
+// up or overwrite the tree with stale results.
+func (state *AppState) startRefresh() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	state.mu.Lock()
+	if state.refreshCancel != nil {
+		state.refreshCancel()
+	}
+	state.refreshCancel = cancel
+	state.mu.Unlock()
+	return ctx, cancel
+}
+
+// fetchTreeNodes lists the resources of the selected kind matching
+// searchQuery, as tree nodes by namespace. It doesn't touch the widgets, so
+// it can run off the UI goroutine.
+func (state *AppState) fetchTreeNodes(ctx context.Context, searchQuery string) (namespacesWithNodes map[string][]*tview.TreeNode, err error) {
+	start := time.Now()
+	defer func() {
+		switch {
+		case ctx.Err() != nil:
+			state.logger.Debug("tree refresh superseded", "kind", state.selectedKind, "query", searchQuery)
+		case err == nil:
+			state.logger.Debug("refreshed the tree", "kind", state.selectedKind, "query", searchQuery, "namespaces", len(namespacesWithNodes), "took", time.Since(start).Round(time.Millisecond))
+		}
+	}()
+
 	select {
 	case <-state.k8sClientsReady:
 	default:
-		return fmt.Errorf("Kubernetes clients are not initialized yet")
+		return nil, fmt.Errorf("Kubernetes clients are not initialized yet")
 	}
 
-	rootNode := tview.NewTreeNode("Namespaces").SetColor(tcell.ColorGreen)
+	if state.selectedKind != podsKind {
+		return state.fetchNamespacesWithResources(ctx, searchQuery)
+	}
+	namespacesWithNodes = make(map[string][]*tview.TreeNode)
+	namespacesWithPods, err := state.fetchNamespacesWithPods(ctx, searchQuery)
+	for nsName, podList := range namespacesWithPods {
+		for _, pod := range podList {
+			namespacesWithNodes[nsName] = append(namespacesWithNodes[nsName], state.newPodNode(pod))
+		}
+	}
+	return namespacesWithNodes, err
+}
+
+// applyTreeNodes replaces the tree with the fetched nodes, or with fetchErr,
+// keeping the expanded namespaces and the selection.
+func (state *AppState) applyTreeNodes(namespacesWithNodes map[string][]*tview.TreeNode, fetchErr error) error {
+	rootNode := tview.NewTreeNode("Namespaces").SetColor(state.theme.rootNode)
 	existingRoot := state.treeView.GetRoot()
 	if existingRoot != nil {
 		state.recordExpansionState(existingRoot)
 	}
 
-	var previouslySelectedPodNamespace, previouslySelectedPodName string
+	var previouslySelectedNamespace, previouslySelectedName string
 	currentNode := state.treeView.GetCurrentNode()
 	if currentNode != nil {
-		if podMeta, ok := currentNode.GetReference().(*metav1.PartialObjectMetadata); ok {
-			previouslySelectedPodNamespace = podMeta.Namespace
-			previouslySelectedPodName = podMeta.Name
+		if obj, ok := currentNode.GetReference().(metav1.Object); ok {
+			previouslySelectedNamespace = obj.GetNamespace()
+			previouslySelectedName = obj.GetName()
 		}
 	}
 
-	namespacesWithPods, err := state.fetchNamespacesWithPods(searchQuery)
-	if err != nil {
-		return err
+	if fetchErr != nil {
+		// Show the error in place of the tree, rather than an empty result
+		rootNode.AddChild(tview.NewTreeNode(tview.Escape(describeAPIError(fetchErr))).SetColor(state.theme.error))
+		state.treeView.SetRoot(rootNode)
+		state.treeView.SetCurrentNode(rootNode)
+		return fetchErr
 	}
 
 	var namespaceNames []string
-	for nsName := range namespacesWithPods {
+	for nsName := range namespacesWithNodes {
 		namespaceNames = append(namespaceNames, nsName)
 	}
 	sort.Strings(namespaceNames)
 
 	for _, nsName := range namespaceNames {
-		podList := namespacesWithPods[nsName]
-		nsNode := tview.NewTreeNode(nsName).SetColor(tcell.ColorYellow)
+		nsNode := tview.NewTreeNode(nsName).SetColor(state.theme.namespaceNode)
 		if expanded, exists := state.namespaceExpansionState[nsName]; exists {
 			nsNode.SetExpanded(expanded)
 		} else {
@@ -280,101 +550,343 @@ func (state *AppState) updatePodTreeView(searchQuery string) error {
 			}
 		}(nsNode))
 
-		podsNode := tview.NewTreeNode("Pods").SetColor(tcell.ColorWhite)
-		podsNode.SetExpanded(true)
-
-		for _, podMeta := range podList {
-			podMetaCopy := podMeta
-			podNode := tview.NewTreeNode(podMeta.Name).SetReference(&podMetaCopy).SetColor(tcell.ColorWhite)
-			podNode.SetSelectedFunc(func() {
-				state.treeView.SetCurrentNode(podNode)
-				state.handlePodSelection(podNode)
-			})
-			podsNode.AddChild(podNode)
+		kindNode := tview.NewTreeNode(state.selectedKind).SetColor(state.theme.kindNode)
+		kindNode.SetExpanded(true)
+		for _, node := range namespacesWithNodes[nsName] {
+			kindNode.AddChild(node)
 		}
-		nsNode.AddChild(podsNode)
+		nsNode.AddChild(kindNode)
 		rootNode.AddChild(nsNode)
 	}
 
 	if len(rootNode.GetChildren()) == 0 {
-		rootNode.AddChild(tview.NewTreeNode("No matching pods found").SetColor(tcell.ColorRed))
+		rootNode.AddChild(tview.NewTreeNode(fmt.Sprintf("No matching %s found", strings.ToLower(state.selectedKind))).SetColor(state.theme.error))
 	}
 
 	state.treeView.SetRoot(rootNode)
 	state.treeView.SetCurrentNode(rootNode)
 
-	state.restorePreviousSelection(rootNode, previouslySelectedPodNamespace, previouslySelectedPodName)
+	state.restorePreviousSelection(rootNode, previouslySelectedNamespace, previouslySelectedName)
 
 	return nil
 }
 
-func (state *AppState) fetchNamespacesWithPods(searchQuery string) (map[string][]metav1.PartialObjectMetadata, error) {
-	namespacesWithPods := make(map[string][]metav1.PartialObjectMetadata)
+// newPodNode creates the tree node for a pod, wired to show its details
+// when selected.
+func (state *AppState) newPodNode(pod v1.Pod) *tview.TreeNode {
+	podNode := tview.NewTreeNode(podNodeText(&pod)).SetReference(&pod).SetColor(state.podPhaseColor(pod.Status.Phase))
+	podNode.SetSelectedFunc(func() {
+		state.treeView.SetCurrentNode(podNode)
+		state.handlePodSelection(podNode)
+	})
+	return podNode
+}
 
-	if state.selectedNamespace == "all" {
-		namespaceList, err := state.clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+// isLabelSelector reports whether a search query should be sent to the API
+// server as a label selector, like app=nginx,tier!=frontend, instead of
+// being matched against pod names. A regex search, starting with '/', is
+// never one, even when the pattern contains '='.
+func isLabelSelector(searchQuery string) bool {
+	return !strings.HasPrefix(searchQuery, "/") && strings.Contains(searchQuery, "=")
+}
+
+// parseSearchQuery splits a search query into the label selector to list
+// with and the function that filters the listed names. With fuzzy, a plain
+// name query matches names containing its characters in order.
+func parseSearchQuery(searchQuery string, fuzzy bool) (labelSelector string, matchesName func(name string) bool, err error) {
+	// A leading '/' switches the name search from substring to regex matching
+	if strings.HasPrefix(searchQuery, "/") {
+		re, err := regexp.Compile(searchQuery[1:])
 		if err != nil {
-			return nil, err
+			return "", nil, fmt.Errorf("invalid regex search: %v", err)
 		}
-		for _, ns := range namespaceList.Items {
-			nsName := ns.Name
-			podList, err := state.fetchPodMetadataList(nsName)
-			if err != nil {
+		return "", re.MatchString, nil
+	}
+
+	if isLabelSelector(searchQuery) {
+		return strings.TrimSpace(searchQuery), func(string) bool { return true }, nil
+	}
+
+	if fuzzy {
+		return "", func(name string) bool {
+			_, ok := fuzzyScore(searchQuery, name)
+			return ok
+		}, nil
+	}
+
+	return "", func(name string) bool {
+		return strings.Contains(strings.ToLower(name), strings.ToLower(searchQuery))
+	}, nil
+}
+
+// isFuzzyQuery reports whether searchQuery is matched and ranked fuzzily.
+func (state *AppState) isFuzzyQuery(searchQuery string) bool {
+	return state.fuzzySearch && searchQuery != "" && !isLabelSelector(searchQuery) && !strings.HasPrefix(searchQuery, "/")
+}
+
+// searchScope returns the namespace pods are listed in for searchQuery.
+// Global search looks for matching pods in every namespace, but only while
+// there is a query, so toggling it never lists the whole cluster.
+func (state *AppState) searchScope(searchQuery string) string {
+	if state.globalSearch && searchQuery != "" {
+		return "all"
+	}
+	return state.selectedNamespace
+}
+
+// namespaceListConcurrency bounds how many namespaces are listed at once in
+// "all" mode.
+const namespaceListConcurrency = 10
+
+// listInNamespaces calls list for namespace, or for every namespace when it
+// is "all", leaving out the hidden ones. In "all" mode the namespaces are
+// listed concurrently, so list must be safe to call from several goroutines,
+// and namespaces that fail to list are skipped unless they all fail.
+func (state *AppState) listInNamespaces(ctx context.Context, namespace string, list func(namespace string) error) error {
+	if namespace != "all" {
+		return list(namespace)
+	}
+
+	var namespaceList *v1.NamespaceList
+	err := withRetry(ctx, func() (err error) {
+		namespaceList, err = state.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var g errgroup.Group
+	var mu sync.Mutex
+	var failures int
+	var firstErr error
+	g.SetLimit(namespaceListConcurrency)
+	namespaces := slices.DeleteFunc(namespaceList.Items, func(ns v1.Namespace) bool {
+		return state.hidesNamespace(ns.Name)
+	})
+	for _, ns := range namespaces {
+		namespace := ns.Name
+		g.Go(func() error {
+			if err := list(namespace); err != nil {
+				mu.Lock()
+				failures++
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	// Skipping a few namespaces is fine, but if none could be listed an
+	// empty tree would hide the reason
+	if failures > 0 && failures == len(namespaces) {
+		return firstErr
+	}
+	return nil
+}
+
+func (state *AppState) fetchNamespacesWithPods(ctx context.Context, searchQuery string) (map[string][]v1.Pod, error) {
+	namespacesWithPods := make(map[string][]v1.Pod)
+
+	labelSelector, matchesName, err := parseSearchQuery(searchQuery, state.fuzzySearch)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	err = state.listInNamespaces(ctx, state.searchScope(searchQuery), func(namespace string) error {
+		var podList *v1.PodList
+		err := withRetry(ctx, func() (err error) {
+			podList, err = state.fetchPodList(ctx, namespace, labelSelector)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		var pods []v1.Pod
+		for _, pod := range podList.Items {
+			if !matchesName(pod.Name) {
 				continue
 			}
-			if len(podList.Items) > 0 {
-				namespacesWithPods[nsName] = podList.Items
+			if state.phaseFilter != "" && pod.Status.Phase != state.phaseFilter {
+				continue
 			}
+			pods = append(pods, pod)
 		}
-	} else {
-		podList, err := state.fetchPodMetadataList(state.selectedNamespace)
-		if err != nil {
-			return nil, err
+		if len(pods) > 0 {
+			// Sorting by usage, if on, is stable and so keeps the best
+			// matches first among pods with the same usage
+			if state.isFuzzyQuery(searchQuery) {
+				sortByFuzzyScore(searchQuery, pods)
+			}
+			state.sortPodsByUsage(ctx, namespace, pods)
+			mu.Lock()
+			namespacesWithPods[namespace] = pods
+			mu.Unlock()
 		}
-		if len(podList.Items) > 0 {
-			namespacesWithPods[state.selectedNamespace] = podList.Items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespacesWithPods, nil
+}
+
+// fetchPodList lists the full pod objects, rather than only their metadata,
+// so the tree can show each pod's status.
+func (state *AppState) fetchPodList(ctx context.Context, namespace, labelSelector string) (*v1.PodList, error) {
+	listOptions := metav1.ListOptions{
+		LabelSelector: labelSelector,
+	}
+	if *state.nodeFilter != "" {
+		listOptions.FieldSelector = "spec.nodeName=" + *state.nodeFilter
+	}
+	return state.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+}
+
+// podNodeText renders a pod's tree label: its name, ready containers, phase
+// and a dimmed age. The ready count is red until every container is ready.
+func podNodeText(pod *v1.Pod) string {
+	ready := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
 		}
 	}
+	readyText := fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+	if ready < len(pod.Spec.Containers) {
+		readyText = "[red]" + readyText + "[-]"
+	}
+	return fmt.Sprintf("%s %s (%s) [::d]%s[::-]", pod.Name, readyText, pod.Status.Phase, formatAge(pod.CreationTimestamp.Time))
+}
 
-	if searchQuery != "" {
-		for nsName, podList := range namespacesWithPods {
-			var matchingPods []metav1.PartialObjectMetadata
-			for _, podMeta := range podList {
-				if strings.Contains(strings.ToLower(podMeta.Name), strings.ToLower(searchQuery)) {
-					matchingPods = append(matchingPods, podMeta)
-				}
-			}
-			if len(matchingPods) > 0 {
-				namespacesWithPods[nsName] = matchingPods
-			} else {
-				delete(namespacesWithPods, nsName)
-			}
+// formatAge renders the time since t the way kubectl does, e.g. "45m" or "3d".
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// phaseFilters is the order in which 'P' cycles the tree's phase filter. The
+// empty phase shows pods in every phase.
+var phaseFilters = []v1.PodPhase{"", v1.PodRunning, v1.PodPending, v1.PodFailed, v1.PodSucceeded}
+
+func (state *AppState) cyclePhaseFilter() {
+	for i, phase := range phaseFilters {
+		if phase == state.phaseFilter {
+			state.phaseFilter = phaseFilters[(i+1)%len(phaseFilters)]
+			break
 		}
 	}
 
-	return namespacesWithPods, nil
+	state.updateTreeTitle()
 }
 
-func (state *AppState) fetchPodMetadataList(namespace string) (*metav1.PartialObjectMetadataList, error) {
-	podList, err := state.dynamicClient.Resource(schema.GroupVersionResource{
-		Group:    "",
-		Version:  "v1",
-		Resource: "pods",
-	}).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "PartialObjectMetadataList",
-			APIVersion: "meta.k8s.io/v1",
-		},
-	})
-	if err != nil {
-		return nil, err
+// hidesNamespace reports whether namespace is one of the system namespaces
+// left out when listing every namespace. Picking it in the dropdown still
+// shows it.
+func (state *AppState) hidesNamespace(namespace string) bool {
+	return !state.showHiddenNamespaces && slices.Contains(state.hiddenNamespaces, namespace)
+}
+
+// toggleHiddenNamespaces reveals the system namespaces hidden when listing
+// every namespace, or hides them again.
+func (state *AppState) toggleHiddenNamespaces() {
+	if len(state.hiddenNamespaces) == 0 {
+		state.setStatus("No namespaces are hidden, see hiddenNamespaces in the config file", statusInfo)
+		return
 	}
-	var metadataList metav1.PartialObjectMetadataList
-	err = runtime.DefaultUnstructuredConverter.FromUnstructured(podList.UnstructuredContent(), &metadataList)
-	if err != nil {
-		return nil, err
+	state.showHiddenNamespaces = !state.showHiddenNamespaces
+	names := strings.Join(state.hiddenNamespaces, ", ")
+	if state.showHiddenNamespaces {
+		state.setStatus("Showing the system namespaces: "+names, statusInfo)
+	} else {
+		state.setStatus("Hiding the system namespaces: "+names, statusInfo)
+	}
+}
+
+// toggleGlobalSearch switches between searching the selected namespace and
+// searching every namespace.
+func (state *AppState) toggleGlobalSearch() {
+	state.globalSearch = !state.globalSearch
+	if state.globalSearch {
+		state.setStatus("Search now looks in every namespace", statusInfo)
+	} else {
+		state.setStatus("Search now looks in the selected namespace", statusInfo)
+	}
+	state.updateTreeTitle()
+}
+
+// toggleFuzzySearch switches name searches between substring and fuzzy
+// matching.
+func (state *AppState) toggleFuzzySearch() {
+	state.fuzzySearch = !state.fuzzySearch
+	if state.fuzzySearch {
+		state.setStatus("Search now matches pod names fuzzily, best matches first", statusInfo)
+	} else {
+		state.setStatus("Search now matches pod names by substring", statusInfo)
+	}
+	state.updateTreeTitle()
+}
+
+// updateTreeTitle shows the listed resource kind and the active pod filters
+// in the tree's title.
+func (state *AppState) updateTreeTitle() {
+	title := "Namespaces and " + state.selectedKind
+	if state.selectedKind != podsKind {
+		state.treeView.SetTitle(title)
+		return
+	}
+
+	var filters []string
+	if state.globalSearch {
+		filters = append(filters, "global search")
+	}
+	if state.fuzzySearch {
+		filters = append(filters, "fuzzy")
+	}
+	if state.phaseFilter != "" {
+		filters = append(filters, fmt.Sprintf("%s only", state.phaseFilter))
+	}
+	if *state.nodeFilter != "" {
+		filters = append(filters, fmt.Sprintf("node %s", *state.nodeFilter))
+	}
+	if state.podSort != sortByName {
+		filters = append(filters, fmt.Sprintf("by %s", state.podSort))
+	}
+
+	if len(filters) == 0 {
+		state.treeView.SetTitle(title)
+	} else {
+		state.treeView.SetTitle(fmt.Sprintf("%s (%s)", title, strings.Join(filters, ", ")))
+	}
+}
+
+func (state *AppState) podPhaseColor(phase v1.PodPhase) tcell.Color {
+	switch phase {
+	case v1.PodRunning:
+		return state.theme.success
+	case v1.PodPending:
+		return state.theme.warning
+	case v1.PodFailed:
+		return state.theme.error
+	case v1.PodSucceeded:
+		return state.theme.muted
+	default:
+		return state.theme.podNode
 	}
-	return &metadataList, nil
 }
 
 func (state *AppState) recordExpansionState(node *tview.TreeNode) {
@@ -387,16 +899,75 @@ func (state *AppState) recordExpansionState(node *tview.TreeNode) {
 	}
 }
 
+// setAllNamespacesExpanded expands or collapses every namespace node, and
+// records it so the next refresh keeps them that way.
+func (state *AppState) setAllNamespacesExpanded(expanded bool) {
+	root := state.treeView.GetRoot()
+	if root == nil {
+		return
+	}
+	for _, nsNode := range root.GetChildren() {
+		if len(nsNode.GetChildren()) == 0 {
+			continue
+		}
+		nsNode.SetExpanded(expanded)
+		state.namespaceExpansionState[nsNode.GetText()] = expanded
+	}
+	if !expanded {
+		state.treeView.SetCurrentNode(root)
+	}
+}
+
+// jumpToNamespace moves the selection to the namespace step places away
+// from the one holding the current node, skipping over its children. Going
+// back from inside a namespace lands on that namespace first.
+func (state *AppState) jumpToNamespace(step int) {
+	root := state.treeView.GetRoot()
+	if root == nil {
+		return
+	}
+	namespaces := root.GetChildren()
+	if len(namespaces) == 0 {
+		return
+	}
+
+	node := state.treeView.GetCurrentNode()
+	current := -1
+	for node != nil && node != root {
+		parent := findParentNode(root, node)
+		if parent == root {
+			current = slices.Index(namespaces, node)
+			if step < 0 && node != state.treeView.GetCurrentNode() {
+				current++
+			}
+			break
+		}
+		node = parent
+	}
+
+	var target int
+	switch {
+	case current < 0 && step < 0:
+		target = len(namespaces) - 1
+	case current < 0:
+		target = 0
+	default:
+		target = min(max(current+step, 0), len(namespaces)-1)
+	}
+	state.treeView.SetCurrentNode(namespaces[target])
+	state.handleNodeSelection(namespaces[target])
+}
+
 func (state *AppState) restorePreviousSelection(rootNode *tview.TreeNode, namespace, podName string) {
 	var findPodNode func(node *tview.TreeNode, namespace, podName string) (*tview.TreeNode, *tview.TreeNode, *tview.TreeNode)
 	findPodNode = func(node *tview.TreeNode, namespace, podName string) (*tview.TreeNode, *tview.TreeNode, *tview.TreeNode) {
 		if node.GetText() == namespace {
 			for _, child := range node.GetChildren() {
-				if child.GetText() == "Pods" {
+				if child.GetText() == state.selectedKind {
 					podsNode := child
 					for _, podNode := range podsNode.GetChildren() {
-						if podMeta, ok := podNode.GetReference().(*metav1.PartialObjectMetadata); ok {
-							if podMeta.Namespace == namespace && podMeta.Name == podName {
+						if obj, ok := podNode.GetReference().(metav1.Object); ok {
+							if obj.GetNamespace() == namespace && obj.GetName() == podName {
 								return podNode, podsNode, node
 							}
 						}
@@ -430,100 +1001,236 @@ func (state *AppState) restorePreviousSelection(rootNode *tview.TreeNode, namesp
 	}
 }
 
+// handleNodeSelection shows the details of whatever the tree's current node
+// refers to.
+func (state *AppState) handleNodeSelection(node *tview.TreeNode) {
+	switch ref := node.GetReference().(type) {
+	case *appsv1.Deployment:
+		state.showWorkloadDetails(ref.Name+"-deployment", formatDeploymentDetails(ref))
+	case *appsv1.StatefulSet:
+		state.showWorkloadDetails(ref.Name+"-statefulset", formatStatefulSetDetails(ref))
+	case *appsv1.DaemonSet:
+		state.showWorkloadDetails(ref.Name+"-daemonset", formatDaemonSetDetails(ref))
+	case *batchv1.Job:
+		state.showWorkloadDetails(ref.Name+"-job", formatJobDetails(ref))
+	case *batchv1.CronJob:
+		state.showWorkloadDetails(ref.Name+"-cronjob", formatCronJobDetails(ref))
+	case *v1.Service:
+		state.showWorkloadDetails(ref.Name+"-service", formatServiceDetails(ref))
+	case *v1.ConfigMap:
+		state.showWorkloadDetails(ref.Name+"-configmap", formatConfigMapDetails(ref))
+	case *v1.Secret:
+		state.showWorkloadDetails(ref.Name+"-secret", formatSecretDetails(ref, false))
+	default:
+		state.handlePodSelection(node)
+	}
+}
+
+// podSelectionTimeout bounds fetching the pod and metrics of a selected
+// pod, so an unreachable API server doesn't leave the details loading.
+const podSelectionTimeout = 15 * time.Second
+
+// handlePodSelection shows the details of the pod of node. They're fetched
+// off the UI goroutine, and dropped if the selection moved on meanwhile.
 func (state *AppState) handlePodSelection(node *tview.TreeNode) {
-	if podMeta, ok := node.GetReference().(*metav1.PartialObjectMetadata); ok {
-		state.isPodHighlighted = true
-		podName := podMeta.Name
-		podNamespace := podMeta.Namespace
+	state.resetOutput("")
+	podRef, ok := node.GetReference().(*v1.Pod)
+	if !ok {
+		state.isPodHighlighted = false
+		state.secondSection.SetText("No pod is highlighted.")
+		return
+	}
 
-		metrics, err := state.getPodMetrics(podNamespace, podName)
-		if err != nil {
-			state.secondSection.SetText(fmt.Sprintf("Error fetching metrics for pod '%s': %v[-]", podName, err))
-			return
-		}
+	state.isPodHighlighted = true
+	podName := podRef.Name
+	podNamespace := podRef.Namespace
+	label := podName + "-details"
+	state.outputLabel = label
+	state.secondSection.SetText(fmt.Sprintf("Loading pod '%s'…", podName))
 
-		pod, err := state.clientset.CoreV1().Pods(podNamespace).Get(context.TODO(), podName, metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				state.secondSection.SetText(fmt.Sprintf("Pod '%s' in namespace '%s' not found.[-]", podName, podNamespace))
-				state.isPodHighlighted = false
-				state.setFocusHighlight(state.treeView)
+	cs := state.clientset
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), podSelectionTimeout)
+		defer cancel()
+
+		// Without metrics-server the details are still worth showing, without usage
+		metrics, _ := state.getPodMetrics(ctx, podNamespace, podName)
+
+		var pod *v1.Pod
+		err := withRetry(ctx, func() (err error) {
+			pod, err = cs.CoreV1().Pods(podNamespace).Get(ctx, podName, metav1.GetOptions{})
+			return err
+		})
+
+		state.app.QueueUpdateDraw(func() {
+			if state.outputLabel != label || state.treeView.GetCurrentNode() != node {
 				return
 			}
-			state.secondSection.SetText(fmt.Sprintf("Error fetching pod details: %v[-]", err))
+			if err != nil {
+				if errors.IsNotFound(err) {
+					state.secondSection.SetText(fmt.Sprintf("Pod '%s' in namespace '%s' not found.[-]", podName, podNamespace))
+					state.isPodHighlighted = false
+					state.setFocusHighlight(state.treeView)
+					return
+				}
+				state.secondSection.SetText(fmt.Sprintf("Error fetching pod details: %v[-]", err))
+				return
+			}
+
+			state.secondSection.SetText(state.formatPodDetails(pod, metrics))
+			state.watchPodDetails(pod, metrics)
+		})
+	}()
+}
+
+func (state *AppState) deletePod(podName, podNamespace string) {
+	state.secondSection.SetText(fmt.Sprintf("Deleting pod '%s' in namespace '%s'...", podName, podNamespace))
+	searchQuery := state.searchInput.GetText()
+	go func() {
+		err := state.clientset.CoreV1().Pods(podNamespace).Delete(context.TODO(), podName, metav1.DeleteOptions{})
+		if err != nil {
+			state.app.QueueUpdateDraw(func() {
+				state.secondSection.SetText(fmt.Sprintf("[red]Error deleting pod '%s': %v[-]", podName, err))
+			})
 			return
 		}
 
-		formattedText := state.formatPodDetails(pod, metrics)
-		state.secondSection.SetText(formattedText)
-	} else {
-		state.isPodHighlighted = false
-		state.secondSection.SetText("No pod is highlighted.")
-	}
+		state.refreshTreeInBackground(searchQuery, func(err error) {
+			if err != nil {
+				state.secondSection.SetText(fmt.Sprintf("Pod '%s' deleted, but refreshing pods failed: %v", podName, err))
+				return
+			}
+			state.secondSection.SetText(fmt.Sprintf("[green]Pod '%s' in namespace '%s' deleted.[-]", podName, podNamespace))
+		})
+	}()
 }
 
-func (state *AppState) getPodMetrics(namespace, podName string) (*PodMetrics, error) {
+func (state *AppState) getPodMetrics(ctx context.Context, namespace, podName string) (*PodMetrics, error) {
 	state.mu.Lock()
 	mc := state.metricsClient
 	state.mu.Unlock()
 
 	if mc == nil {
-		return nil, fmt.Errorf("metrics client is not initialized")
+		return nil, fmt.Errorf("metrics are unavailable, is metrics-server installed?")
 	}
 
-	podMetrics, err := mc.MetricsV1beta1().PodMetricses(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	podMetrics, err := mc.MetricsV1beta1().PodMetricses(namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
 	var totalCPU, totalMemory int64
+	var containers []ContainerMetrics
 	for _, container := range podMetrics.Containers {
 		cpuQty := container.Usage.Cpu().MilliValue()
 		memQty := container.Usage.Memory().Value()
 		totalCPU += cpuQty
 		totalMemory += memQty
+		containers = append(containers, ContainerMetrics{
+			Name:          container.Name,
+			CPUMillicores: cpuQty,
+			MemoryBytes:   memQty,
+		})
 	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
 
 	cpuUsage := fmt.Sprintf("%dm", totalCPU)
 	memoryUsage := formatBytes(totalMemory)
 
 	return &PodMetrics{
-		CPU:    cpuUsage,
-		Memory: memoryUsage,
+		CPU:           cpuUsage,
+		Memory:        memoryUsage,
+		CPUMillicores: totalCPU,
+		MemoryBytes:   totalMemory,
+		Containers:    containers,
 	}, nil
 }
 
 type PodMetrics struct {
 	CPU    string
 	Memory string
+
+	CPUMillicores int64
+	MemoryBytes   int64
+	Containers    []ContainerMetrics
 }
 
+// ContainerMetrics is the usage of a single container of a pod.
+type ContainerMetrics struct {
+	Name          string
+	CPUMillicores int64
+	MemoryBytes   int64
+}
+
+// container returns the usage of the named container, if metrics-server
+// reported it.
+func (m *PodMetrics) container(name string) (ContainerMetrics, bool) {
+	if m == nil {
+		return ContainerMetrics{}, false
+	}
+	for _, container := range m.Containers {
+		if container.Name == name {
+			return container, true
+		}
+	}
+	return ContainerMetrics{}, false
+}
+
+// formatBytes renders a byte count with binary units, e.g. "1.5 MiB".
+// Negative counts, which bad metric data can produce, keep their sign.
 func formatBytes(bytes int64) string {
+	if bytes < 0 {
+		// Negating math.MinInt64 overflows, so step through uint64
+		return "-" + formatUnsignedBytes(uint64(-(bytes+1))+1)
+	}
+	return formatUnsignedBytes(uint64(bytes))
+}
+
+func formatUnsignedBytes(bytes uint64) string {
 	const unit = 1024
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
 	}
-	div, exp := int64(unit), 0
+	div, exp := uint64(unit), 0
 	for n := bytes / unit; n >= unit; n /= unit {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// highRestartCount is where a container's restart count turns red in the
+// pod details.
+const highRestartCount = 5
+
 func (state *AppState) formatPodDetails(pod *v1.Pod, metrics *PodMetrics) string {
 	podName := pod.Name
 	podNamespace := pod.Namespace
 	podPhase := string(pod.Status.Phase)
 	podIP := pod.Status.PodIP
 	nodeName := pod.Spec.NodeName
-	startTime := pod.Status.StartTime.Format("2006-01-02 15:04:05")
+	// Pods that haven't been scheduled yet have no start time
+	startTime := "Not started"
+	if pod.Status.StartTime != nil {
+		startTime = pod.Status.StartTime.Format("2006-01-02 15:04:05")
+	}
 	hostIP := pod.Status.HostIP
 
 	var sb strings.Builder
 	sb.WriteString("[::b]Metrics:[::-]\n")
-	sb.WriteString(fmt.Sprintf("CPU Usage: [yellow]%s[-]\n", metrics.CPU))
-	sb.WriteString(fmt.Sprintf("Memory Usage: [yellow]%s[-]\n\n", metrics.Memory))
+	if metrics == nil {
+		sb.WriteString("[gray]Metrics unavailable, is metrics-server installed?[-]\n\n")
+	} else {
+		if len(metrics.Containers) > 1 {
+			for _, container := range metrics.Containers {
+				sb.WriteString(fmt.Sprintf("- %s: CPU [yellow]%dm[-], Memory [yellow]%s[-]\n", container.Name, container.CPUMillicores, formatBytes(container.MemoryBytes)))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("CPU Usage: [yellow]%s[-]\n", metrics.CPU))
+		sb.WriteString(fmt.Sprintf("Memory Usage: [yellow]%s[-]\n\n", metrics.Memory))
+	}
+
+	sb.WriteString(state.formatPodResources(pod, metrics))
 
 	sb.WriteString("[::b]Pod Information:[::-]\n")
 	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", podName))
@@ -534,26 +1241,83 @@ func (state *AppState) formatPodDetails(pod *v1.Pod, metrics *PodMetrics) string
 	sb.WriteString(fmt.Sprintf("Host IP: [yellow]%s[-]\n", hostIP))
 	sb.WriteString(fmt.Sprintf("Start Time: [yellow]%s[-]\n", startTime))
 
+	if state.metadataMode != metadataHidden {
+		sb.WriteString("\n")
+		sb.WriteString(state.formatPodMetadata(pod, state.metadataMode))
+	}
+
 	sb.WriteString("\n[::b]Containers:[::-]\n")
 	for _, container := range pod.Spec.Containers {
-		containerName := container.Name
-		var containerStatus string
-		for _, status := range pod.Status.ContainerStatuses {
-			if status.Name == containerName {
-				if status.State.Running != nil {
-					containerStatus = "Running"
-				} else if status.State.Waiting != nil {
-					containerStatus = "Waiting"
-				} else if status.State.Terminated != nil {
-					containerStatus = "Terminated"
-				} else {
-					containerStatus = "Unknown"
-				}
+		var status *v1.ContainerStatus
+		for i := range pod.Status.ContainerStatuses {
+			if pod.Status.ContainerStatuses[i].Name == container.Name {
+				status = &pod.Status.ContainerStatuses[i]
 				break
 			}
 		}
-		sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-]\n", containerName, containerStatus))
+		if status == nil {
+			sb.WriteString(fmt.Sprintf("- %s: [yellow]Not started[-]\n", container.Name))
+			sb.WriteString(containerImageText(container, status))
+			continue
+		}
+
+		restartColor := "yellow"
+		if status.RestartCount >= highRestartCount {
+			restartColor = "red"
+		}
+		sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-], restarts [%s]%d[-]\n", container.Name, containerStateText(status.State), restartColor, status.RestartCount))
+		sb.WriteString(containerImageText(container, status))
+		if last := status.LastTerminationState.Terminated; last != nil {
+			sb.WriteString(fmt.Sprintf("  Last terminated: [red]%s[-] (exit code %d) at %s\n", orNone(last.Reason), last.ExitCode, last.FinishedAt.Format("2006-01-02 15:04:05")))
+		}
+	}
+
+	if len(pod.Spec.InitContainers) > 0 {
+		sb.WriteString("\n[::b]Init Containers:[::-]\n")
+		for _, container := range pod.Spec.InitContainers {
+			stateText := "Not started"
+			var status *v1.ContainerStatus
+			for i := range pod.Status.InitContainerStatuses {
+				if pod.Status.InitContainerStatuses[i].Name == container.Name {
+					status = &pod.Status.InitContainerStatuses[i]
+					stateText = containerStateText(status.State)
+					break
+				}
+			}
+			sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-]\n", container.Name, stateText))
+			sb.WriteString(containerImageText(container, status))
+		}
 	}
 
 	return sb.String()
 }
+
+// containerImageText shows the image a container asks for, its pull policy,
+// and the image actually running, which status is nil or lacks until the
+// image has been pulled.
+func containerImageText(container v1.Container, status *v1.ContainerStatus) string {
+	imageID := "[gray]not pulled yet[-]"
+	if status != nil && status.ImageID != "" {
+		imageID = "[yellow]" + tview.Escape(status.ImageID) + "[-]"
+	}
+	return fmt.Sprintf("  Image: [yellow]%s[-] (pull policy %s)\n  Image ID: %s\n", tview.Escape(container.Image), orNone(string(container.ImagePullPolicy)), imageID)
+}
+
+// containerStateText describes a container state with its reason, and the
+// exit code once it has terminated, e.g. "Terminated (Error, exit code 1)".
+func containerStateText(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil && state.Waiting.Reason != "":
+		return fmt.Sprintf("Waiting (%s)", state.Waiting.Reason)
+	case state.Waiting != nil:
+		return "Waiting"
+	case state.Terminated != nil && state.Terminated.Reason != "":
+		return fmt.Sprintf("Terminated (%s, exit code %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated (exit code %d)", state.Terminated.ExitCode)
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1 << 20, "1.0 MiB"},
+		{1<<20 - 1, "1024.0 KiB"},
+		{5 << 40, "5.0 TiB"},
+		{math.MaxInt64, "8.0 EiB"},
+		{-1, "-1 B"},
+		{-1024, "-1.0 KiB"},
+		{-3 << 30, "-3.0 GiB"},
+		{math.MinInt64, "-8.0 EiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.bytes); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPodDetailsPendingPod(t *testing.T) {
+	// Pods waiting to be scheduled have no start time yet
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "web", Image: "nginx:1.27"}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodPending},
+	}
+
+	state := &AppState{metadataMode: metadataHidden}
+	details := state.formatPodDetails(pod, nil)
+	if !strings.Contains(details, "Start Time: [yellow]Not started[-]") {
+		t.Errorf("formatPodDetails of a pod without a start time doesn't say it hasn't started:\n%s", details)
+	}
+	if !strings.Contains(details, "Phase: [yellow]Pending[-]") {
+		t.Errorf("formatPodDetails doesn't show the Pending phase:\n%s", details)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query         string
+		labelSelector string
+		matches       string
+		misses        string
+	}{
+		{"app=nginx", "app=nginx", "anything", ""},
+		{"/^web-", "", "web-1", "api-web-1"},
+		{"/foo=bar", "", "foo=bar", "foo"},
+		{"Web", "", "my-web-1", "api"},
+	}
+	for _, tt := range tests {
+		labelSelector, matchesName, err := parseSearchQuery(tt.query, false)
+		if err != nil {
+			t.Fatalf("parseSearchQuery(%q) error: %v", tt.query, err)
+		}
+		if labelSelector != tt.labelSelector {
+			t.Errorf("parseSearchQuery(%q) label selector = %q, want %q", tt.query, labelSelector, tt.labelSelector)
+		}
+		if !matchesName(tt.matches) {
+			t.Errorf("parseSearchQuery(%q) doesn't match %q", tt.query, tt.matches)
+		}
+		if tt.misses != "" && matchesName(tt.misses) {
+			t.Errorf("parseSearchQuery(%q) matches %q", tt.query, tt.misses)
+		}
+	}
+
+	if _, _, err := parseSearchQuery("/[", false); err == nil {
+		t.Error("parseSearchQuery(\"/[\") returned no error for an invalid regex")
+	}
+}
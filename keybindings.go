@@ -0,0 +1,363 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// action names something a key can trigger. The names double as the keys of
+// the keybindings section of the config file.
+type action string
+
+const (
+	actionContext          action = "context"
+	actionNamespace        action = "namespace"
+	actionKind             action = "kind"
+	actionToggleTerminal   action = "toggle-terminal"
+	actionSearch           action = "search"
+	actionRefresh          action = "refresh"
+	actionQuit             action = "quit"
+	actionTimestamps       action = "timestamps"
+	actionStopPortForward  action = "stop-port-forward"
+	actionPhaseFilter      action = "phase-filter"
+	actionNodeUsage        action = "node-usage"
+	actionPromQL           action = "promql"
+	actionSort             action = "sort"
+	actionSaveOutput       action = "save-output"
+	actionRevealSecret     action = "reveal-secret"
+	actionStopTail         action = "stop-tail"
+	actionGraphs           action = "graphs"
+	actionGraphRange       action = "graph-range"
+	actionYAML             action = "yaml"
+	actionDescribe         action = "describe"
+	actionEvents           action = "events"
+	actionLogs             action = "logs"
+	actionLogsSince        action = "logs-since"
+	actionPreviousLogs     action = "previous-logs"
+	actionTail             action = "tail"
+	actionTailHere         action = "tail-here"
+	actionDelete           action = "delete"
+	actionRestart          action = "restart"
+	actionPortForward      action = "port-forward"
+	actionCopyFiles        action = "copy-files"
+	actionExec             action = "exec"
+	actionExecCustom       action = "exec-custom"
+	actionHelp             action = "help"
+	actionFavorite         action = "favorite"
+	actionDebug            action = "debug"
+	actionScale            action = "scale"
+	actionExpandAll        action = "expand-all"
+	actionCollapseAll      action = "collapse-all"
+	actionCopyValue        action = "copy-value"
+	actionCopyCommand      action = "copy-command"
+	actionGlobalSearch     action = "global-search"
+	actionFuzzySearch      action = "fuzzy-search"
+	actionMetadata         action = "metadata"
+	actionTopPods          action = "top-pods"
+	actionQuotas           action = "quotas"
+	actionPrevNamespace    action = "previous-namespace"
+	actionNextNamespace    action = "next-namespace"
+	actionLogPanel         action = "log-panel"
+	actionSystemNamespaces action = "system-namespaces"
+)
+
+// keyBinding ties an action to the keys that trigger it. The first key is
+// the one shown to the user; the label goes in the helper text and the
+// description in the help modal.
+type keyBinding struct {
+	action      action
+	keys        string
+	label       string
+	description string
+}
+
+// defaultKeyBindings lists every action in the order the help modal shows
+// them.
+var defaultKeyBindings = []keyBinding{
+	{actionToggleTerminal, "oO", "Toggle Terminals", "Toggle between terminal output and UI output"},
+	{actionLogs, "l", "Logs", "View pod logs"},
+	{actionLogsSince, "L", "Logs since", "View pod logs since a duration (e.g. 30m, 1h)"},
+	{actionPreviousLogs, "p", "Previous Logs", "View logs of the previous container instance"},
+	{actionTail, "t", "Tail Logs", "Tail logs in real-time (new terminal)"},
+	{actionTailHere, "T", "Tail Logs here", "Tail logs in real-time inside Podminator"},
+	{actionStopTail, "x", "Stop tail", "Stop tailing logs inside Podminator"},
+	{actionTimestamps, "z", "Toggle timestamps", "Toggle timestamps on log lines"},
+	{actionExec, "e", "Exec", "Open a shell in a pod, bash if available or sh"},
+	{actionExecCustom, "E", "Exec with custom command", "Exec a custom command in a pod"},
+	{actionDebug, "X", "Debug container", "Start an ephemeral debug container in a pod and attach to it"},
+	{actionDescribe, "iI", "Info", "Show detailed pod information (describe)"},
+	{actionMetadata, "a", "Labels/annotations", "Cycle the labels and annotations in the pod details: hidden, without system annotations, all"},
+	{actionEvents, "v", "Events", "Show pod events, newest first"},
+	{actionRevealSecret, "D", "Reveal secret", "Reveal the decoded values of the selected Secret"},
+	{actionDelete, "d", "Delete", "Delete the pod (asks for confirmation)"},
+	{actionRestart, "R", "Restart workload", "Rolling restart of the pod's workload"},
+	{actionScale, "#", "Scale", "Scale the highlighted Deployment or StatefulSet, or the one managing the pod"},
+	{actionPortForward, "f", "Port-forward", "Port-forward to the pod (localPort:podPort)"},
+	{actionStopPortForward, "F", "Stop port-forward", "Stop the active port-forward"},
+	{actionCopyFiles, "u", "Copy files", "Copy files to or from the pod"},
+	{actionYAML, "yY", "YAML", "Show pod YAML"},
+	{actionCopyValue, "B", "Copy name/IP/node", "Copy the pod's name, IP or node to the clipboard"},
+	{actionCopyCommand, "W", "Copy kubectl command", "Copy the kubectl command for an action on the pod, e.g. its logs, to the clipboard"},
+	{actionGraphs, "h", "Metrics Graphs", "Show CPU, memory and network I/O graphs"},
+	{actionGraphRange, "H", "Graph range", "Change the graph range and step, then show the graphs"},
+	{actionPromQL, "g", "PromQL query", "Run a PromQL query and plot its first series"},
+	{actionTopPods, "m", "Top pods", "Rank the pods using the most CPU, or memory when pressed again"},
+	{actionQuotas, "A", "Quotas", "Show the resource quotas and limit ranges of the highlighted namespace"},
+	{actionNodeUsage, "M", "Node usage", "Show every node's CPU and memory usage"},
+	{actionContext, "cC", "Context", "Switch between contexts"},
+	{actionNamespace, "nN", "Namespace", "Switch between namespaces"},
+	{actionFavorite, "b", "Favorite namespace", "Mark the highlighted namespace as a favorite, or unmark it"},
+	{actionSystemNamespaces, "V", "System namespaces", "Show or hide the system namespaces, like kube-system, when listing every namespace"},
+	{actionKind, "K", "Resource kind", "Switch the listed resource kind"},
+	{actionExpandAll, "+", "Expand all", "Expand every namespace in the tree"},
+	{actionCollapseAll, "-", "Collapse all", "Collapse every namespace in the tree"},
+	{actionPrevNamespace, "[", "Previous namespace", "Jump to the previous namespace in the tree"},
+	{actionNextNamespace, "]", "Next namespace", "Jump to the next namespace in the tree"},
+	{actionSearch, "sS", "Search", "Focus on the search input field"},
+	{actionGlobalSearch, "G", "Global search", "Toggle searching pods in every namespace, whatever namespace is selected"},
+	{actionFuzzySearch, "Z", "Fuzzy search", "Toggle fuzzy matching of names, ranking pods by match quality"},
+	{actionPhaseFilter, "P", "Filter by phase", "Cycle the phase filter"},
+	{actionSort, "U", "Sort by usage", "Cycle the pod order: by name, CPU or memory"},
+	{actionRefresh, "r", "Refresh", "Refresh the tree"},
+	{actionSaveOutput, "w", "Save output", "Save the output section to a text file"},
+	{actionLogPanel, "J", "Podminator log", "Show Podminator's own log: connections, refreshes and errors"},
+	{actionHelp, "?", "Help", "Show this help"},
+	{actionQuit, "qQ", "Quit", "Quit the application"},
+}
+
+// mutatingActions change the cluster or run commands in it, so --read-only
+// turns them off.
+var mutatingActions = map[action]bool{
+	actionExec:       true,
+	actionExecCustom: true,
+	actionDebug:      true,
+	actionDelete:     true,
+	actionRestart:    true,
+	actionScale:      true,
+	actionCopyFiles:  true,
+}
+
+// metricsActions need the metrics API, so they are off on clusters without
+// metrics-server.
+var metricsActions = map[action]bool{
+	actionTopPods: true,
+	actionSort:    true,
+}
+
+// unavailableReason explains why a can't be used right now, or returns ""
+// when it can.
+func (state *AppState) unavailableReason(a action) string {
+	switch {
+	case *state.readOnly && mutatingActions[a]:
+		return "disabled in read-only mode"
+	case metricsActions[a] && !state.metricsAvailable():
+		return "unavailable, the cluster doesn't serve the metrics API (metrics-server)"
+	case a == actionGraphs && state.prometheusAPI() == nil && !state.metricsAvailable():
+		return "unavailable without Prometheus or metrics-server"
+	}
+	return ""
+}
+
+// activeKeyBindings are the bindings that can be used, leaving out the
+// mutating ones in read-only mode and the metrics ones without metrics.
+func (state *AppState) activeKeyBindings() []keyBinding {
+	var active []keyBinding
+	for _, binding := range state.keyBindings {
+		if state.unavailableReason(binding.action) == "" {
+			active = append(active, binding)
+		}
+	}
+	return active
+}
+
+// reservedKeys are handled before the keybindings get to see them: space and
+// '/' in the output section, and h/j/k/l with --vim.
+func reservedKeys(vim bool) string {
+	if vim {
+		return " /hjkl"
+	}
+	return " /"
+}
+
+// searchKeys step through the matches while the output section is searched.
+// The namespace switch keeps them by default, but they can't be remapped to
+// anything else.
+const searchKeys = "nN"
+
+// vimKeyBindings replace the default keys that --vim takes over for
+// navigation.
+var vimKeyBindings = map[action]string{
+	actionLogs:   ".",
+	actionGraphs: "%",
+}
+
+// resolveKeyBindings applies the overrides from the config file, which map an
+// action name to the characters that should trigger it, on top of the
+// defaults, or the vim ones with vim. It fails on unknown actions, on
+// reserved keys and on keys bound to two actions.
+func resolveKeyBindings(overrides map[string]string, vim bool) ([]keyBinding, map[rune]action, error) {
+	known := make(map[action]bool, len(defaultKeyBindings))
+	for _, binding := range defaultKeyBindings {
+		known[binding.action] = true
+	}
+	reserved := reservedKeys(vim)
+	for name, keys := range overrides {
+		if !known[action(name)] {
+			return nil, nil, fmt.Errorf("unknown action '%s' in keybindings, expected one of: %s", name, actionNames())
+		}
+		if keys == "" {
+			return nil, nil, fmt.Errorf("no key given for action '%s' in keybindings", name)
+		}
+		for _, key := range keys {
+			if strings.ContainsRune(searchKeys, key) && action(name) != actionNamespace {
+				return nil, nil, fmt.Errorf("key '%c' of action '%s' in keybindings is reserved for searching the output", key, name)
+			}
+		}
+	}
+
+	bindings := make([]keyBinding, len(defaultKeyBindings))
+	copy(bindings, defaultKeyBindings)
+	actions := make(map[rune]action)
+	for i, binding := range bindings {
+		if keys, ok := vimKeyBindings[binding.action]; ok && vim {
+			bindings[i].keys = keys
+		}
+		if keys, ok := overrides[string(binding.action)]; ok {
+			bindings[i].keys = keys
+		}
+		for _, key := range bindings[i].keys {
+			if strings.ContainsRune(reserved, key) {
+				return nil, nil, fmt.Errorf("key '%c' of action '%s' is reserved, the keys %q can't be bound", key, binding.action, reserved)
+			}
+			if other, taken := actions[key]; taken {
+				return nil, nil, fmt.Errorf("key '%c' is bound to both '%s' and '%s'", key, other, binding.action)
+			}
+			actions[key] = binding.action
+		}
+	}
+	return bindings, actions, nil
+}
+
+// keyFor returns the first key bound to a, as shown in the helper text.
+func (state *AppState) keyFor(a action) string {
+	for _, binding := range state.keyBindings {
+		if binding.action == a {
+			return string([]rune(binding.keys)[0])
+		}
+	}
+	return ""
+}
+
+// actionFor returns the action bound to the key pressed, or "" when there is
+// none.
+func (state *AppState) actionFor(event *tcell.EventKey) action {
+	if event.Key() != tcell.KeyRune {
+		return ""
+	}
+	return state.keyActions[event.Rune()]
+}
+
+// helperActions are the actions the helper text lists, so it fits its row;
+// the help lists every one of them.
+var helperActions = map[action]bool{
+	actionLogs:     true,
+	actionExec:     true,
+	actionDescribe: true,
+	actionEvents:   true,
+	actionGraphs:   true,
+	actionDelete:   true,
+	actionSearch:   true,
+	actionRefresh:  true,
+	actionHelp:     true,
+	actionQuit:     true,
+}
+
+// keyBindingsHelp renders the active bindings of helperActions for the
+// helper text.
+func (state *AppState) keyBindingsHelp() string {
+	var parts []string
+	for _, binding := range state.activeKeyBindings() {
+		if !helperActions[binding.action] {
+			continue
+		}
+		key := []rune(binding.keys)[0]
+		label := binding.label
+		if binding.action == actionHelp {
+			label = "Help, with every key"
+		}
+		parts = append(parts, fmt.Sprintf("[yellow]'%c'[-] %s", key, label))
+	}
+	return " " + strings.Join(parts, " | ") + " "
+}
+
+// actionNames lists the actions that can be remapped, for error messages.
+func actionNames() string {
+	names := make([]string, 0, len(defaultKeyBindings))
+	for _, binding := range defaultKeyBindings {
+		names = append(names, string(binding.action))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// keyDisplay shows the keys of a binding the way the help modal lists them,
+// e.g. "o, O".
+func keyDisplay(keys string) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, string(key))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// showHelpModal lists every action with its keys and description, followed
+// by the keys that only work in one section. Escape closes it.
+func (state *AppState) showHelpModal() {
+	previousFocus := state.app.GetFocus()
+
+	var b strings.Builder
+	row := func(keys, description string) {
+		fmt.Fprintf(&b, "  [yellow]%-12s[-] %s\n", tview.Escape(keys), description)
+	}
+	fmt.Fprintf(&b, "[::b]Actions[::-]\n")
+	if *state.readOnly {
+		fmt.Fprintf(&b, "  [red]Read-only mode:[-] exec, debug, copy, delete, restart and scale are off\n")
+	}
+	for _, binding := range state.activeKeyBindings() {
+		row(keyDisplay(binding.keys), binding.description)
+	}
+	fmt.Fprintf(&b, "\n[::b]Output section[::-]\n")
+	row("space", "Jump to the bottom")
+	row("/", "Search the output")
+	row("n, N", "Next and previous match")
+	row("Esc", "Clear the search")
+	fmt.Fprintf(&b, "\n[::b]Navigation[::-]\n")
+	row("Up, Down", "Move through the tree or scroll the output")
+	row("Enter", "Expand a node or select a pod")
+	row("Left, Right", "Move between the tree and the output")
+	if *state.vimMode {
+		row("j, k", "Move down and up")
+		row("h, l", "Collapse and expand, or move between sections")
+	}
+
+	help := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(b.String())
+	help.SetBorder(true).SetTitle(" Help (Esc to close) ")
+	help.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			state.pages.RemovePage("helpModal")
+			state.modalActive = false
+			state.setFocusHighlight(previousFocus)
+		}
+	})
+
+	state.pages.AddPage("helpModal", help, true, true)
+	state.modalActive = true
+	state.app.SetFocus(help)
+}
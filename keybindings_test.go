@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestResolveKeyBindingsVim(t *testing.T) {
+	_, actions, err := resolveKeyBindings(nil, true)
+	if err != nil {
+		t.Fatalf("resolveKeyBindings with vim: %v", err)
+	}
+	for _, key := range "hjkl" {
+		if a, ok := actions[key]; ok {
+			t.Errorf("key '%c' is bound to '%s' with vim", key, a)
+		}
+	}
+	if actions['.'] != actionLogs || actions['%'] != actionGraphs {
+		t.Errorf("logs and graphs are bound to '.' and '%%' with vim, got '%s' and '%s'", actions['.'], actions['%'])
+	}
+
+	if _, _, err := resolveKeyBindings(map[string]string{"logs": "l"}, true); err == nil {
+		t.Error("binding logs to 'l' with vim returned no error")
+	}
+	if _, _, err := resolveKeyBindings(map[string]string{"refresh": "n"}, false); err == nil {
+		t.Error("binding refresh to 'n' returned no error")
+	}
+	if _, _, err := resolveKeyBindings(map[string]string{"namespace": "nN"}, false); err != nil {
+		t.Errorf("keeping namespace on 'nN': %v", err)
+	}
+}
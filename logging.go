@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/klog/v2"
+)
+
+// logPanelLines is how many of the latest log entries the log panel keeps.
+const logPanelLines = 500
+
+// logRing keeps the last lines written to it, oldest first, for the log
+// panel. The log handler writes one entry per call.
+type logRing struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+func newLogRing(size int) *logRing {
+	return &logRing{lines: make([]string, size)}
+}
+
+func (r *logRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines[r.next] = strings.TrimRight(string(p), "\n")
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// Lines returns the kept lines, oldest first.
+func (r *logRing) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// openLog sets up state.logger, which keeps the latest entries for the log
+// panel and also writes them to the file at path, when set. The app owns
+// the terminal while it runs, so nothing may be printed to stdout or
+// stderr: the standard log package and client-go's klog are routed to the
+// same place.
+func (state *AppState) openLog(path string) error {
+	state.logRing = newLogRing(logPanelLines)
+	var out io.Writer = state.logRing
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		state.logOutput = file
+		out = io.MultiWriter(state.logRing, file)
+	}
+
+	state.logger = slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	slog.SetDefault(state.logger)
+	klog.SetSlogLogger(state.logger)
+	return nil
+}
+
+// closeLog closes the log file, if any.
+func (state *AppState) closeLog() {
+	if state.logOutput != nil {
+		state.logOutput.Close()
+	}
+}
+
+// showLogPanel shows the latest entries of Podminator's own log, newest at
+// the bottom, with warnings in yellow and errors in red.
+func (state *AppState) showLogPanel() {
+	previousFocus := state.app.GetFocus()
+
+	lines := state.logRing.Lines()
+	var b strings.Builder
+	if len(lines) == 0 {
+		b.WriteString("Nothing logged yet\n")
+	}
+	for _, line := range lines {
+		switch {
+		case strings.Contains(line, "level=ERROR"):
+			fmt.Fprintf(&b, "[red]%s[-]\n", tview.Escape(line))
+		case strings.Contains(line, "level=WARN"):
+			fmt.Fprintf(&b, "[yellow]%s[-]\n", tview.Escape(line))
+		default:
+			fmt.Fprintf(&b, "%s\n", tview.Escape(line))
+		}
+	}
+
+	title := fmt.Sprintf(" Podminator log, last %d entries (Esc to close) ", logPanelLines)
+	if *state.logFile != "" {
+		title = fmt.Sprintf(" Podminator log, also in %s (Esc to close) ", shortenHome(*state.logFile))
+	}
+	panel := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(b.String())
+	panel.SetBorder(true).SetTitle(title)
+	panel.ScrollToEnd()
+	closePanel := func() {
+		state.pages.RemovePage("logPanel")
+		state.modalActive = false
+		state.setFocusHighlight(previousFocus)
+	}
+	panel.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			closePanel()
+		}
+	})
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if state.actionFor(event) == actionLogPanel {
+			closePanel()
+			return nil
+		}
+		return event
+	})
+
+	state.pages.AddPage("logPanel", panel, true, true)
+	state.modalActive = true
+	state.app.SetFocus(panel)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+)
+
+// streamLogs follows a container's logs straight into secondSection, without
+// needing an external terminal. The stream runs until stopLogStream is called
+// or the container's log ends.
+func (state *AppState) streamLogs(podName, podNamespace, containerName string) {
+	state.resetOutput(podName + "-" + containerName + "-logs")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	state.mu.Lock()
+	state.logStreamCancel = cancel
+	state.logStreamDesc = fmt.Sprintf("%s/%s %s", podNamespace, podName, containerName)
+	state.logStreamID++
+	id := state.logStreamID
+	cs := state.clientset
+	state.mu.Unlock()
+
+	state.secondSection.SetText(fmt.Sprintf("[yellow]Following logs for %s/%s (press 'x' to stop)[-]\n\n", podName, containerName))
+
+	go func() {
+		// A stream that ends by itself is no longer running, unless a newer
+		// one has replaced it already
+		defer state.app.QueueUpdate(func() {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			if state.logStreamID == id && state.logStreamCancel != nil {
+				state.logStreamCancel()
+				state.logStreamCancel = nil
+				state.logStreamDesc = ""
+			}
+		})
+
+		logOpts := &v1.PodLogOptions{
+			Container:  containerName,
+			Follow:     true,
+			Timestamps: state.showTimestamps,
+		}
+		if *state.tailLines > 0 {
+			tailLines := int64(*state.tailLines)
+			logOpts.TailLines = &tailLines
+		}
+		req := cs.CoreV1().Pods(podNamespace).GetLogs(podName, logOpts)
+		stream, err := req.Stream(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				state.app.QueueUpdateDraw(func() {
+					fmt.Fprintf(state.secondSection, "[red]Error streaming logs: %v[-]\n", err)
+				})
+			}
+			return
+		}
+		defer stream.Close()
+
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			line := scanner.Text()
+			state.app.QueueUpdateDraw(func() {
+				if ctx.Err() == nil {
+					fmt.Fprintln(state.secondSection, tview.Escape(line))
+				}
+			})
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			state.app.QueueUpdateDraw(func() {
+				fmt.Fprintf(state.secondSection, "[red]Log stream ended: %v[-]\n", err)
+			})
+		}
+	}()
+}
+
+// stopLogStream cancels the active log stream, if any, and reports whether
+// there was one to stop.
+func (state *AppState) stopLogStream() bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.logStreamCancel == nil {
+		return false
+	}
+	state.logStreamCancel()
+	state.logStreamCancel = nil
+	state.logStreamDesc = ""
+	return true
+}
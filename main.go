@@ -9,7 +9,12 @@ func main() {
 	appState := &AppState{
 		useNewTerminal:          false,
 		selectedNamespace:       "all",
+		selectedKind:            podsKind,
+		podSort:                 sortByName,
+		topPodsSort:             sortByCPU,
+		metadataMode:            metadataHidden,
 		namespaceExpansionState: make(map[string]bool),
+		contextExpansionStates:  make(map[string]map[string]bool),
 		k8sClientsReady:         make(chan struct{}),
 		mu:                      sync.Mutex{},
 	}
@@ -17,10 +22,17 @@ func main() {
 	appState.initializeApp()
 	appState.detectPrometheus()
 	appState.initializeUI()
+	appState.showConnecting()
 	appState.loadContexts()
 	go appState.periodicPodRefresh()
 
-	if err := appState.app.Run(); err != nil {
+	err := appState.app.Run()
+	appState.stopPortForward()
+	appState.stopPodWatch()
+	appState.stopPodDetailsWatch()
+	appState.stopCopy()
+	appState.closeLog()
+	if err != nil {
 		panic(err)
 	}
 }
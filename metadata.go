@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+)
+
+// metadataMode is how much of a pod's labels and annotations the details
+// show.
+type metadataMode string
+
+const (
+	metadataHidden   metadataMode = "hidden"
+	metadataFiltered metadataMode = "filtered"
+	metadataAll      metadataMode = "all"
+)
+
+var metadataModes = []metadataMode{metadataHidden, metadataFiltered, metadataAll}
+
+func (state *AppState) cycleMetadataMode() {
+	for i, mode := range metadataModes {
+		if mode == state.metadataMode {
+			state.metadataMode = metadataModes[(i+1)%len(metadataModes)]
+			break
+		}
+	}
+
+	switch state.metadataMode {
+	case metadataHidden:
+		state.setStatus("Labels and annotations hidden", statusInfo)
+	case metadataFiltered:
+		state.setStatus("Showing labels and annotations, without system annotations", statusInfo)
+	case metadataAll:
+		state.setStatus("Showing labels and every annotation", statusInfo)
+	}
+}
+
+// isSystemAnnotation reports whether an annotation is set by Kubernetes or
+// its tooling, like kubectl.kubernetes.io/last-applied-configuration, rather
+// than by the pod's owners.
+func isSystemAnnotation(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return strings.HasSuffix(prefix, "kubernetes.io") || strings.HasSuffix(prefix, "k8s.io") ||
+		strings.HasPrefix(prefix, "cni.") || strings.HasSuffix(prefix, "projectcalico.org")
+}
+
+// formatPodMetadata renders the pod's labels and annotations as aligned
+// key/value pairs, leaving out system annotations unless mode is
+// metadataAll.
+func (state *AppState) formatPodMetadata(pod *v1.Pod, mode metadataMode) string {
+	annotations := make(map[string]string, len(pod.Annotations))
+	hidden := 0
+	for key, value := range pod.Annotations {
+		if mode != metadataAll && isSystemAnnotation(key) {
+			hidden++
+			continue
+		}
+		annotations[key] = value
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[::b]Labels:[::-]\n")
+	sb.WriteString(state.formatKeyValues(pod.Labels))
+	sb.WriteString("\n[::b]Annotations:[::-]\n")
+	sb.WriteString(state.formatKeyValues(annotations))
+	if hidden > 0 {
+		sb.WriteString(fmt.Sprintf("%s%d system annotations hidden, press 'a' to show them[-]\n", colorTag(state.theme.muted), hidden))
+	}
+	return sb.String()
+}
+
+// formatKeyValues lists m sorted by key, with the values lined up.
+func (state *AppState) formatKeyValues(m map[string]string) string {
+	if len(m) == 0 {
+		return "<none>\n"
+	}
+	width := 0
+	for key := range m {
+		width = max(width, len(key))
+	}
+	var sb strings.Builder
+	for _, key := range sortedKeys(m) {
+		sb.WriteString(fmt.Sprintf("%-*s  %s%s[-]\n", width, tview.Escape(key), colorTag(state.theme.value), tview.Escape(m[key])))
+	}
+	return sb.String()
+}
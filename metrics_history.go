@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// metricsSampleInterval matches the default metrics-server resolution.
+	metricsSampleInterval = 15 * time.Second
+	// maxMetricsSamples keeps an hour of samples per pod.
+	maxMetricsSamples = 240
+)
+
+// metricsSample is one metrics-server reading of a pod, taken at time.
+type metricsSample struct {
+	time          time.Time
+	cpuMillicores float64
+	memoryMB      float64
+}
+
+// trackPodMetrics starts recording metrics-server samples for a pod, so its
+// graphs can be drawn without Prometheus. The first call also starts the
+// sampler.
+func (state *AppState) trackPodMetrics(podName, podNamespace string) {
+	key := podNamespace + "/" + podName
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.metricsHistory == nil {
+		state.metricsHistory = make(map[string][]metricsSample)
+		go state.sampleMetrics()
+	}
+	if _, ok := state.metricsHistory[key]; !ok {
+		state.metricsHistory[key] = nil
+		go state.samplePodMetrics(podName, podNamespace)
+	}
+}
+
+// sampleMetrics records a sample of every tracked pod at each interval.
+// Pods that are no longer in the tree, because they are gone or another
+// namespace or search is shown, stop being tracked.
+func (state *AppState) sampleMetrics() {
+	ticker := time.NewTicker(metricsSampleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var shown map[string]bool
+		state.app.QueueUpdate(func() {
+			shown = state.treePodKeys()
+		})
+
+		state.mu.Lock()
+		keys := make([]string, 0, len(state.metricsHistory))
+		for key := range state.metricsHistory {
+			if !shown[key] {
+				delete(state.metricsHistory, key)
+				continue
+			}
+			keys = append(keys, key)
+		}
+		state.mu.Unlock()
+
+		for _, key := range keys {
+			podNamespace, podName, _ := strings.Cut(key, "/")
+			state.samplePodMetrics(podName, podNamespace)
+		}
+	}
+}
+
+// samplePodMetrics appends the current usage of a pod to its history. Pods
+// that no longer exist stop being tracked.
+func (state *AppState) samplePodMetrics(podName, podNamespace string) {
+	key := podNamespace + "/" + podName
+
+	state.mu.Lock()
+	mc := state.metricsClient
+	state.mu.Unlock()
+	if mc == nil {
+		return
+	}
+
+	podMetrics, err := mc.MetricsV1beta1().PodMetricses(podNamespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		state.logger.Debug("sampling pod metrics", "pod", podName, "namespace", podNamespace, "err", err)
+		if errors.IsNotFound(err) {
+			state.mu.Lock()
+			delete(state.metricsHistory, key)
+			state.mu.Unlock()
+		}
+		return
+	}
+
+	sample := metricsSample{time: podMetrics.Timestamp.Time}
+	for _, container := range podMetrics.Containers {
+		sample.cpuMillicores += float64(container.Usage.Cpu().MilliValue())
+		sample.memoryMB += float64(container.Usage.Memory().Value()) / (1024 * 1024)
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if samples, ok := state.metricsHistory[key]; ok {
+		// metrics-server answers with the same reading until its next scrape
+		if len(samples) > 0 && !sample.time.After(samples[len(samples)-1].time) {
+			return
+		}
+		samples = append(samples, sample)
+		if len(samples) > maxMetricsSamples {
+			samples = samples[len(samples)-maxMetricsSamples:]
+		}
+		state.metricsHistory[key] = samples
+	}
+}
+
+// showMetricsServerGraphs draws a pod's CPU and memory graphs from the
+// samples collected since it was first tracked.
+func (state *AppState) showMetricsServerGraphs(podName, podNamespace string) {
+	state.trackPodMetrics(podName, podNamespace)
+	state.resetOutput(podName + "-metrics")
+
+	state.mu.Lock()
+	samples := append([]metricsSample(nil), state.metricsHistory[podNamespace+"/"+podName]...)
+	state.mu.Unlock()
+
+	if len(samples) < 2 {
+		state.secondSection.SetText(fmt.Sprintf("Prometheus Not detected, collecting samples from metrics-server instead (every %s).\n\nPress '%s' again in a minute to see the graphs.", metricsSampleInterval, state.keyFor(actionGraphs)))
+		return
+	}
+
+	times := make([]time.Time, len(samples))
+	cpuData := make([]float64, len(samples))
+	memData := make([]float64, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.time
+		cpuData[i] = sample.cpuMillicores
+		memData[i] = sample.memoryMB
+	}
+
+	caption := fmt.Sprintf("metrics-server, %d samples over the last %s", len(samples), formatDuration(times[len(times)-1].Sub(times[0])))
+	state.showGraphs(func(width int) string {
+		cpuGraph := plotTimePoints(times, cpuData, fmt.Sprintf("CPU Usage (milicores) - %s", caption), width, 20)
+		memGraph := plotTimePoints(times, memData, fmt.Sprintf("Memory Usage (MiB) - %s", caption), width, 10)
+		return fmt.Sprintf("%s\n\n%s", cpuGraph, memGraph)
+	})
+	state.setFocusHighlight(state.secondSection)
+}
+
+// treePodKeys returns the namespace/name of every pod in the tree. It must
+// run on the UI goroutine.
+func (state *AppState) treePodKeys() map[string]bool {
+	keys := make(map[string]bool)
+	root := state.treeView.GetRoot()
+	if root == nil {
+		return keys
+	}
+	root.Walk(func(node, parent *tview.TreeNode) bool {
+		if pod, ok := node.GetReference().(*v1.Pod); ok {
+			keys[pod.Namespace+"/"+pod.Name] = true
+		}
+		return true
+	})
+	return keys
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeUsageThreshold is the usage percentage above which a node is
+// highlighted in the overview.
+const nodeUsageThreshold = 80
+
+// nodeUsage is a node's usage next to its allocatable capacity.
+type nodeUsage struct {
+	name                     string
+	cpuUsage, cpuAllocatable int64
+	memUsage, memAllocatable int64
+	hasMetrics               bool
+}
+
+// showNodeOverview lists every node's CPU and memory usage against its
+// allocatable capacity.
+func (state *AppState) showNodeOverview() {
+	state.resetOutput("nodes")
+	state.secondSection.SetText("Loading node metrics...")
+
+	go func() {
+		nodes, err := state.fetchNodeUsage()
+		state.app.QueueUpdateDraw(func() {
+			if err != nil {
+				state.secondSection.SetText(fmt.Sprintf("[red]Error loading nodes: %s[-]", describeAPIError(err)))
+				return
+			}
+			state.secondSection.SetText(formatNodeOverview(nodes))
+			state.setFocusHighlight(state.secondSection)
+		})
+	}()
+}
+
+// fetchNodeUsage joins the node list with metrics-server's node metrics.
+// Nodes are still listed when metrics-server is unavailable.
+func (state *AppState) fetchNodeUsage() ([]nodeUsage, error) {
+	state.mu.Lock()
+	cs := state.clientset
+	mc := state.metricsClient
+	state.mu.Unlock()
+
+	nodeList, err := cs.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usageByNode := make(map[string]nodeUsage)
+	if mc != nil {
+		if metricsList, err := mc.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{}); err == nil {
+			for _, m := range metricsList.Items {
+				usageByNode[m.Name] = nodeUsage{
+					cpuUsage:   m.Usage.Cpu().MilliValue(),
+					memUsage:   m.Usage.Memory().Value(),
+					hasMetrics: true,
+				}
+			}
+		}
+	}
+
+	nodes := make([]nodeUsage, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		usage := usageByNode[node.Name]
+		usage.name = node.Name
+		usage.cpuAllocatable = node.Status.Allocatable.Cpu().MilliValue()
+		usage.memAllocatable = node.Status.Allocatable.Memory().Value()
+		nodes = append(nodes, usage)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+	return nodes, nil
+}
+
+func formatNodeOverview(nodes []nodeUsage) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[::b]%-40s %-26s %s[::-]\n", "NODE", "CPU (used / allocatable)", "MEMORY (used / allocatable)"))
+	for _, node := range nodes {
+		if !node.hasMetrics {
+			sb.WriteString(fmt.Sprintf("%-40s %-26s %s\n", node.name,
+				fmt.Sprintf("- / %dm", node.cpuAllocatable), fmt.Sprintf("- / %s", formatBytes(node.memAllocatable))))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-40s %s %s\n", node.name,
+			formatNodeResource(fmt.Sprintf("%dm / %dm", node.cpuUsage, node.cpuAllocatable), node.cpuUsage, node.cpuAllocatable, 26),
+			formatNodeResource(fmt.Sprintf("%s / %s", formatBytes(node.memUsage), formatBytes(node.memAllocatable)), node.memUsage, node.memAllocatable, 0)))
+	}
+	sb.WriteString(fmt.Sprintf("\nNodes above %d%% usage are shown in red.\n", nodeUsageThreshold))
+	return sb.String()
+}
+
+// formatNodeResource renders "used / allocatable (N%)" padded to width,
+// in red when the percentage crosses nodeUsageThreshold.
+func formatNodeResource(amounts string, used, allocatable int64, width int) string {
+	percent := 0.0
+	if allocatable > 0 {
+		percent = float64(used) / float64(allocatable) * 100
+	}
+	text := fmt.Sprintf("%-*s", width, fmt.Sprintf("%s (%.0f%%)", amounts, percent))
+	if percent > nodeUsageThreshold {
+		return "[red]" + text + "[-]"
+	}
+	return text
+}
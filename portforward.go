@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// parsePortMapping validates a kubectl port-forward mapping: "port",
+// "localPort:podPort", or ":podPort" and "0:podPort" for a random local port.
+func parsePortMapping(mapping string) (string, error) {
+	mapping = strings.TrimSpace(mapping)
+	localPort, podPort, hasLocal := strings.Cut(mapping, ":")
+	if !hasLocal {
+		podPort, localPort = localPort, ""
+	}
+	if strings.Contains(podPort, ":") {
+		return "", fmt.Errorf("expected localPort:podPort, got '%s'", mapping)
+	}
+	if podPort == "" {
+		return "", fmt.Errorf("no pod port given, expected localPort:podPort")
+	}
+	if port, err := strconv.Atoi(podPort); err != nil || port < 1 || port > 65535 {
+		return "", fmt.Errorf("pod port '%s' is not a number from 1 to 65535", podPort)
+	}
+	if localPort != "" {
+		if port, err := strconv.Atoi(localPort); err != nil || port < 0 || port > 65535 {
+			return "", fmt.Errorf("local port '%s' is not a number from 0 to 65535", localPort)
+		}
+	}
+	return mapping, nil
+}
+
+func (state *AppState) startPortForward(podName, podNamespace, mapping string) {
+	args := []string{"port-forward", "pod/" + podName, "--namespace=" + podNamespace, mapping}
+	if state.useNewTerminal {
+		err := state.runInTerminal(state.kubectlCommand(args...))
+		if err != nil {
+			state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
+		}
+		return
+	}
+
+	state.stopPortForward()
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("kubectl", append(state.kubectlFlags(), args...)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		state.secondSection.SetText(fmt.Sprintf("[red]Error starting port-forward: %v[-]", err))
+		return
+	}
+
+	description := fmt.Sprintf("%s/%s %s", podNamespace, podName, mapping)
+	state.mu.Lock()
+	state.portForwardCmd = cmd
+	state.portForwardDesc = description
+	state.mu.Unlock()
+	state.secondSection.SetText(fmt.Sprintf("[green]Port-forwarding %s (press 'F' to stop)[-]", description))
+	state.updateHelperText()
+
+	go func() {
+		err := cmd.Wait()
+
+		state.mu.Lock()
+		stillActive := state.portForwardCmd == cmd
+		if stillActive {
+			state.portForwardCmd = nil
+			state.portForwardDesc = ""
+		}
+		state.mu.Unlock()
+
+		// Only report exits that weren't requested through stopPortForward
+		if stillActive {
+			state.app.QueueUpdateDraw(func() {
+				state.secondSection.SetText(fmt.Sprintf("[red]Port-forward %s exited: %v[-]\n%s", description, err, stderr.String()))
+				state.updateHelperText()
+			})
+		}
+	}()
+}
+
+// stopPortForward kills the background port-forward, if any, and reports
+// whether there was one to stop.
+func (state *AppState) stopPortForward() bool {
+	state.mu.Lock()
+	cmd := state.portForwardCmd
+	state.portForwardCmd = nil
+	state.portForwardDesc = ""
+	state.mu.Unlock()
+
+	if cmd == nil {
+		return false
+	}
+	cmd.Process.Kill()
+	return true
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		mapping string
+		want    string
+		wantErr bool
+	}{
+		{"8080", "8080", false},
+		{" 8080:80 ", "8080:80", false},
+		{":80", ":80", false},
+		{"0:80", "0:80", false},
+		{"65535:65535", "65535:65535", false},
+		{"", "", true},
+		{"0", "", true},
+		{"0:0", "", true},
+		{"8080:", "", true},
+		{"8080:0", "", true},
+		{"8080:65536", "", true},
+		{"-1:80", "", true},
+		{"http:80", "", true},
+		{"1:2:3", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parsePortMapping(tt.mapping)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePortMapping(%q) = %q, %v, want %q, error %v", tt.mapping, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -2,54 +2,310 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	"github.com/gdamore/tcell/v2"
 	"github.com/prometheus/client_golang/api"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func (state *AppState) detectPrometheus() {
 	if *state.prometheusURL != "" {
 		// Use the provided Prometheus URL
 		client, err := api.NewClient(api.Config{
-			Address: *state.prometheusURL,
+			Address:      *state.prometheusURL,
+			RoundTripper: state.prometheusRoundTripper(),
 		})
 		if err != nil {
-			state.promDetected = false
-			// fmt.Printf("Error creating Prometheus client with provided URL: %v\n", err)
+			state.setPrometheus(nil)
+			state.logger.Error("creating the Prometheus client", "url", *state.prometheusURL, "err", err)
 			return
 		}
 
-		state.promClient = promv1.NewAPI(client)
-		state.promDetected = true
-		// fmt.Printf("Using provided Prometheus URL: %s\n", *state.prometheusURL)
+		state.setPrometheus(promv1.NewAPI(client))
+		state.logger.Info("using Prometheus", "url", *state.prometheusURL)
 		return
 	} else {
-		state.promDetected = false
+		state.setPrometheus(nil)
 	}
 }
 
-// prometheus.go
-func (state *AppState) getPrometheusMetrics(podName, podNamespace string) (cpuData []float64, memData []float64, err error) {
-	if !state.promDetected || state.promClient == nil {
+// prometheusAPI returns the Prometheus client, or nil when Prometheus isn't
+// detected. Detection runs in the background, hence the lock.
+func (state *AppState) prometheusAPI() promv1.API {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.promDetected {
+		return nil
+	}
+	return state.promClient
+}
+
+// setPrometheus swaps in the Prometheus client, nil when there is none.
+func (state *AppState) setPrometheus(client promv1.API) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.promClient = client
+	state.promDetected = client != nil
+}
+
+// prometheusRoundTripper builds the transport for an explicit
+// --prometheus-url, adding the configured credentials to every request.
+func (state *AppState) prometheusRoundTripper() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if *state.prometheusInsecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	var authorization string
+	if *state.prometheusToken != "" {
+		authorization = "Bearer " + *state.prometheusToken
+	} else if *state.prometheusBasicAuth != "" {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(*state.prometheusBasicAuth))
+	}
+	if authorization == "" {
+		return transport
+	}
+	return &authRoundTripper{authorization: authorization, next: transport}
+}
+
+// authRoundTripper sets the Authorization header on each request.
+type authRoundTripper struct {
+	authorization string
+	next          http.RoundTripper
+}
+
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", rt.authorization)
+	return rt.next.RoundTrip(req)
+}
+
+// Well-known names of the Prometheus service in common installs (the
+// community chart, kube-prometheus-stack and the operator), and the
+// namespaces they usually live in.
+var (
+	prometheusServiceNames      = []string{"prometheus-server", "prometheus", "prometheus-operated", "kube-prometheus-stack-prometheus", "prometheus-k8s"}
+	prometheusServiceNamespaces = []string{"monitoring", "prometheus", "kube-prometheus-stack", "observability", "kube-system", "default"}
+)
+
+// discoverPrometheus looks for a well-known Prometheus service in the
+// cluster and queries it through the API server's service proxy, so the
+// graphs work without a port-forward. An explicit --prometheus-url wins.
+func (state *AppState) discoverPrometheus(cs *kubernetes.Clientset, restConfig *rest.Config) {
+	if *state.prometheusURL != "" {
+		return
+	}
+	state.setPrometheus(nil)
+
+	service, port, ok := findPrometheusService(cs)
+	if !ok {
+		return
+	}
+
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return
+	}
+	address := fmt.Sprintf("%s/api/v1/namespaces/%s/services/%s:%s/proxy",
+		strings.TrimSuffix(restConfig.Host, "/"), service.Namespace, service.Name, port)
+	client, err := api.NewClient(api.Config{
+		Address:      address,
+		RoundTripper: transport,
+	})
+	if err != nil {
+		return
+	}
+
+	state.setPrometheus(promv1.NewAPI(client))
+}
+
+// findPrometheusService returns the first well-known Prometheus service
+// found, with the port to reach it on.
+func findPrometheusService(cs *kubernetes.Clientset) (*v1.Service, string, bool) {
+	for _, namespace := range prometheusServiceNamespaces {
+		for _, name := range prometheusServiceNames {
+			service, err := cs.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil || len(service.Spec.Ports) == 0 {
+				continue
+			}
+			return service, prometheusServicePort(service), true
+		}
+	}
+	return nil, "", false
+}
+
+// prometheusServicePort picks the web port of a Prometheus service,
+// preferring the usual names and 9090 over whatever comes first.
+func prometheusServicePort(service *v1.Service) string {
+	for _, port := range service.Spec.Ports {
+		if port.Name == "web" || port.Name == "http" || port.Name == "http-web" || port.Port == 9090 {
+			return strconv.Itoa(int(port.Port))
+		}
+	}
+	return strconv.Itoa(int(service.Spec.Ports[0].Port))
+}
+
+// maxGraphPoints caps how many samples a graph is fed; longer ranges get a
+// coarser step instead of thousands of points.
+const maxGraphPoints = 240
+
+// graphRange is the time window and resolution of the Prometheus graphs. A
+// zero step is picked automatically from the window.
+type graphRange struct {
+	window time.Duration
+	step   time.Duration
+}
+
+// parseGraphRange parses "window [step]", e.g. "6h" or "24h 10m".
+func parseGraphRange(text string) (graphRange, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || len(fields) > 2 {
+		return graphRange{}, fmt.Errorf("expected a range and an optional step, like 6h or 24h 10m")
+	}
+
+	var r graphRange
+	var err error
+	if r.window, err = time.ParseDuration(fields[0]); err != nil || r.window <= 0 {
+		return graphRange{}, fmt.Errorf("range must be a positive duration like 15m, 6h or 24h")
+	}
+	if len(fields) == 2 {
+		if r.step, err = time.ParseDuration(fields[1]); err != nil || r.step <= 0 {
+			return graphRange{}, fmt.Errorf("step must be a positive duration like 30s or 5m")
+		}
+	}
+	return r, nil
+}
+
+// effectiveStep returns the query step, widened when the window would
+// otherwise produce more than maxGraphPoints samples.
+func (r graphRange) effectiveStep() time.Duration {
+	step := r.step
+	if step <= 0 {
+		step = r.window / 120
+	}
+	if minStep := r.window / maxGraphPoints; step < minStep {
+		step = minStep
+	}
+	if step < time.Second {
+		step = time.Second
+	}
+	return step.Round(time.Second)
+}
+
+// input renders the range the way parseGraphRange reads it.
+func (r graphRange) input() string {
+	if r.step > 0 {
+		return formatDuration(r.window) + " " + formatDuration(r.step)
+	}
+	return formatDuration(r.window)
+}
+
+func (r graphRange) String() string {
+	return fmt.Sprintf("last %s, step %s", formatDuration(r.window), formatDuration(r.effectiveStep()))
+}
+
+// formatDuration drops the zero units time.Duration prints, so 8h0m0s
+// reads 8h.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// showPrometheusGraphs renders the CPU and memory graphs of a pod over the
+// selected graph range, falling back to metrics-server samples without
+// Prometheus.
+func (state *AppState) showPrometheusGraphs(podName, podNamespace string) {
+	if state.prometheusAPI() == nil {
+		state.showMetricsServerGraphs(podName, podNamespace)
+		return
+	}
+
+	label := podName + "-metrics"
+	state.resetOutput(label)
+	r := state.graphRange
+	go func() {
+		cpuData, memData, warnings, err := state.getPrometheusMetrics(podName, podNamespace, r)
+		if err != nil {
+			state.app.QueueUpdateDraw(func() {
+				if state.outputLabel != label {
+					return
+				}
+				state.secondSection.SetText(fmt.Sprintf("Error fetching Prometheus metrics: %v", err))
+			})
+			return
+		}
+
+		// Network counters are optional, so their absence doesn't hide the other graphs
+		rxData, txData, netErr := state.getPrometheusNetwork(podName, podNamespace, r)
+
+		step := r.effectiveStep()
+		state.app.QueueUpdateDraw(func() {
+			// Another pod, or another view, may have been picked meanwhile
+			if state.outputLabel != label {
+				return
+			}
+			state.showGraphs(func(width int) string {
+				cpuGraph := state.plotCPUGraph(cpuData, fmt.Sprintf("CPU Usage (milicores) - %s", r), step, width)
+				memGraph := state.plotMemoryGraph(memData, fmt.Sprintf("Memory Usage (MiB) - %s", r), step, width)
+				netGraph := fmt.Sprintf("Network I/O unavailable: %v", netErr)
+				if netErr == nil {
+					netGraph = state.plotNetworkGraph(rxData, txData, fmt.Sprintf("Network I/O - %s", r), step, width)
+				}
+				return fmt.Sprintf("%s\n\n%s\n\n%s", cpuGraph, memGraph, netGraph) + formatPromWarnings(warnings)
+			})
+			if len(warnings) > 0 {
+				state.setStatus(fmt.Sprintf("Prometheus returned %d warning(s), the graphs may be incomplete", len(warnings)), statusWarning)
+			}
+			state.setFocusHighlight(state.secondSection)
+		})
+	}()
+}
+
+// getPrometheusMetrics returns a pod's CPU (millicores) and memory (MiB)
+// over r, with the warnings Prometheus sent back about the queries, e.g.
+// about partial results.
+func (state *AppState) getPrometheusMetrics(podName, podNamespace string, r graphRange) (cpuData []float64, memData []float64, warnings promv1.Warnings, err error) {
+	promClient := state.prometheusAPI()
+	if promClient == nil {
 		err = fmt.Errorf("Prometheus is not detected or not accessible")
 		return
 	}
 
-	// Define the time range for the last 8 hours
 	end := time.Now()
-	start := end.Add(-8 * time.Hour)
-	step := time.Minute * 15 // Data points
+	start := end.Add(-r.window)
+	step := r.effectiveStep()
 
-	// PromQL queries
-	cpuQuery := fmt.Sprintf(`avg (rate (container_cpu_usage_seconds_total{pod="%s",namespace="%s"}[15m]))`, podName, podNamespace)
-	memQuery := fmt.Sprintf(`avg (rate (container_memory_usage_bytes{pod="%s",namespace="%s"}[15m]))`, podName, podNamespace)
+	// PromQL queries, one series per container; the pod-level cgroup
+	// (container="") and the pause container are left out so they aren't
+	// counted twice
+	cpuQuery := fmt.Sprintf(`rate (container_cpu_usage_seconds_total{pod="%s",namespace="%s",container!="",container!="POD"}[15m])`, podName, podNamespace)
+	memQuery := fmt.Sprintf(`container_memory_working_set_bytes{pod="%s",namespace="%s",container!="",container!="POD"}`, podName, podNamespace)
 
 	// Query CPU metrics
-	cpuResult, warnings, err := state.promClient.QueryRange(context.TODO(), cpuQuery, promv1.Range{
+	cpuResult, cpuWarnings, err := promClient.QueryRange(context.TODO(), cpuQuery, promv1.Range{
 		Start: start,
 		End:   end,
 		Step:  step,
@@ -57,9 +313,10 @@ func (state *AppState) getPrometheusMetrics(podName, podNamespace string) (cpuDa
 	if err != nil {
 		return
 	}
-	if len(warnings) > 0 {
-		fmt.Println("Warnings:", warnings)
+	for _, warning := range cpuWarnings {
+		state.logger.Warn("Prometheus query warning", "query", cpuQuery, "warning", warning)
 	}
+	warnings = append(warnings, cpuWarnings...)
 	cpuMatrix, ok := cpuResult.(model.Matrix)
 	if !ok {
 		err = fmt.Errorf("CPU result is not a matrix")
@@ -67,17 +324,15 @@ func (state *AppState) getPrometheusMetrics(podName, podNamespace string) (cpuDa
 	}
 
 	// Process CPU data
-	cpuData = make([]float64, 0)
-	for _, stream := range cpuMatrix {
-		for _, val := range stream.Values {
-			// cpuData = append(cpuData, float64(val.Value))
-			// Multiply CPU value by 1000 to convert to millicores
-			cpuData = append(cpuData, float64(val.Value)*1000)
-		}
+	cpuData, lastSample := sumByTimestamp(cpuMatrix)
+	cpuData = padDataToCurrentTime(cpuData, lastSample, step, time.Now())
+	for i := range cpuData {
+		// Multiply CPU value by 1000 to convert to millicores
+		cpuData[i] *= 1000
 	}
 
 	// Query Memory metrics
-	memResult, warnings, err := state.promClient.QueryRange(context.TODO(), memQuery, promv1.Range{
+	memResult, memWarnings, err := promClient.QueryRange(context.TODO(), memQuery, promv1.Range{
 		Start: start,
 		End:   end,
 		Step:  step,
@@ -85,9 +340,10 @@ func (state *AppState) getPrometheusMetrics(podName, podNamespace string) (cpuDa
 	if err != nil {
 		return
 	}
-	if len(warnings) > 0 {
-		fmt.Println("Warnings:", warnings)
+	for _, warning := range memWarnings {
+		state.logger.Warn("Prometheus query warning", "query", memQuery, "warning", warning)
 	}
+	warnings = append(warnings, memWarnings...)
 	memMatrix, ok := memResult.(model.Matrix)
 	if !ok {
 		err = fmt.Errorf("Memory result is not a matrix")
@@ -95,27 +351,242 @@ func (state *AppState) getPrometheusMetrics(podName, podNamespace string) (cpuDa
 	}
 
 	// Process Memory data
-	memData = make([]float64, 0)
-	for _, stream := range memMatrix {
+	memData, lastSample = sumByTimestamp(memMatrix)
+	memData = padDataToCurrentTime(memData, lastSample, step, time.Now())
+	for i := range memData {
+		// Convert bytes to mebibytes
+		memData[i] /= 1024 * 1024
+	}
+
+	return
+}
+
+// showPromQLGraph runs a user-supplied PromQL expression over the graph
+// range and plots the first series it returns.
+func (state *AppState) showPromQLGraph(query string) {
+	promClient := state.prometheusAPI()
+	if promClient == nil {
+		state.secondSection.SetText("Prometheus Not detected")
+		return
+	}
+
+	state.resetOutput("promql")
+	state.secondSection.SetText(fmt.Sprintf("Running [yellow]%s[-]...", tview.Escape(query)))
+	r := state.graphRange
+	go func() {
+		end := time.Now()
+		result, warnings, err := promClient.QueryRange(context.TODO(), query, promv1.Range{
+			Start: end.Add(-r.window),
+			End:   end,
+			Step:  r.effectiveStep(),
+		})
+
+		state.app.QueueUpdateDraw(func() {
+			if err != nil {
+				state.secondSection.SetText(fmt.Sprintf("[red]Query failed: %s[-]", tview.Escape(err.Error())))
+				return
+			}
+			matrix, ok := result.(model.Matrix)
+			if !ok {
+				state.secondSection.SetText(fmt.Sprintf("[red]Expected a range vector, got a %s[-]", result.Type()))
+				return
+			}
+			state.showGraphs(func(width int) string {
+				return formatPromQLResult(query, matrix, r, width) + formatPromWarnings(warnings)
+			})
+			state.setFocusHighlight(state.secondSection)
+		})
+	}()
+}
+
+func formatPromQLResult(query string, matrix model.Matrix, r graphRange, width int) string {
+	header := fmt.Sprintf("[::b]%s[::-]\nReturned %d series - %s\n", tview.Escape(query), len(matrix), r)
+	if len(matrix) == 0 {
+		return header
+	}
+
+	values := matrix[0].Values
+	data := make([]float64, 0, len(values))
+	for _, val := range values {
+		data = append(data, float64(val.Value))
+	}
+	if len(values) > 0 {
+		data = padDataToCurrentTime(data, values[len(values)-1].Timestamp.Time(), r.effectiveStep(), time.Now())
+	}
+	caption := fmt.Sprintf("Series 1: %s", tview.Escape(matrix[0].Metric.String()))
+	return header + "\n" + plotTimeSeries(data, caption, r.effectiveStep(), width, 20)
+}
+
+// formatPromWarnings lists the warnings of Prometheus queries, to go under
+// their graphs.
+func formatPromWarnings(warnings promv1.Warnings) string {
+	var sb strings.Builder
+	for _, warning := range warnings {
+		sb.WriteString(fmt.Sprintf("\n[yellow]Warning: %s[-]", tview.Escape(warning)))
+	}
+	return sb.String()
+}
+
+// sumByTimestamp adds up the series of a matrix, such as one per container,
+// at each timestamp, so the result is the pod's total over time. Series
+// missing a timestamp contribute nothing to it. It also returns the time of
+// the last sample.
+func sumByTimestamp(matrix model.Matrix) ([]float64, time.Time) {
+	totals := make(map[model.Time]float64)
+	for _, stream := range matrix {
 		for _, val := range stream.Values {
-			// Convert bytes to megabytes
-			memData = append(memData, float64(val.Value)/(1024*1024))
+			totals[val.Timestamp] += float64(val.Value)
 		}
 	}
 
+	timestamps := make([]model.Time, 0, len(totals))
+	for timestamp := range totals {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+	data := make([]float64, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		data = append(data, totals[timestamp])
+	}
+	if len(timestamps) == 0 {
+		return data, time.Time{}
+	}
+	return data, timestamps[len(timestamps)-1].Time()
+}
+
+// padDataToCurrentTime extends data, whose last sample was taken at
+// lastSample, with one point per step up to now. The plots place the last
+// point at the current time, so without padding a lagging scrape would
+// shift the whole series. The padding repeats the last value, which keeps
+// the Y-axis range unchanged.
+func padDataToCurrentTime(data []float64, lastSample time.Time, step time.Duration, now time.Time) []float64 {
+	if len(data) == 0 || step <= 0 || !lastSample.Before(now) {
+		return data
+	}
+
+	missing := int(now.Sub(lastSample) / step)
+	last := data[len(data)-1]
+	for i := 0; i < missing; i++ {
+		data = append(data, last)
+	}
+	return data
+}
+
+// getPrometheusNetwork returns a pod's received and transmitted bytes per
+// second, summed over its interfaces.
+func (state *AppState) getPrometheusNetwork(podName, podNamespace string, r graphRange) (rxData []float64, txData []float64, err error) {
+	rxQuery := fmt.Sprintf(`sum (rate (container_network_receive_bytes_total{pod="%s",namespace="%s"}[5m]))`, podName, podNamespace)
+	txQuery := fmt.Sprintf(`sum (rate (container_network_transmit_bytes_total{pod="%s",namespace="%s"}[5m]))`, podName, podNamespace)
+
+	if rxData, err = state.queryPrometheusSeries(rxQuery, r); err != nil {
+		return
+	}
+	txData, err = state.queryPrometheusSeries(txQuery, r)
 	return
 }
 
-func (state *AppState) plotCPUGraph(cpuData []float64, caption string) string {
+// queryPrometheusSeries runs a range query expected to return a single
+// series, and returns its values.
+func (state *AppState) queryPrometheusSeries(query string, r graphRange) ([]float64, error) {
+	promClient := state.prometheusAPI()
+	if promClient == nil {
+		return nil, fmt.Errorf("Prometheus is not detected or not accessible")
+	}
+	end := time.Now()
+	result, _, err := promClient.QueryRange(context.TODO(), query, promv1.Range{
+		Start: end.Add(-r.window),
+		End:   end,
+		Step:  r.effectiveStep(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("result is not a matrix")
+	}
+
+	data := make([]float64, 0)
+	if len(matrix) == 0 || len(matrix[0].Values) == 0 {
+		return data, nil
+	}
+	values := matrix[0].Values
+	for _, val := range values {
+		data = append(data, float64(val.Value))
+	}
+	return padDataToCurrentTime(data, values[len(values)-1].Timestamp.Time(), r.effectiveStep(), time.Now()), nil
+}
+
+// Graphs fill the output section, but no less than minGraphWidth columns,
+// and defaultGraphWidth before the section has been drawn.
+const (
+	defaultGraphWidth = 80
+	minGraphWidth     = 40
+)
+
+// graphWidth is the width that fits the graphs in the output section.
+func (state *AppState) graphWidth() int {
+	_, _, width, _ := state.secondSection.GetInnerRect()
+	if width <= 0 {
+		return defaultGraphWidth
+	}
+	return max(minGraphWidth, width-1)
+}
+
+// showGraphs displays the graphs drawn by render at the output section's
+// width, and keeps render to draw them again when the terminal is resized.
+func (state *AppState) showGraphs(render func(width int) string) {
+	width := state.graphWidth()
+	state.graphRender = render
+	state.graphRenderedWidth = width
+	state.secondSection.SetText(render(width))
+}
+
+// redrawGraphsOnResize draws the displayed graphs again once the output
+// section's width has changed. It runs after each screen update, so the
+// redraw is queued rather than done in the middle of one.
+func (state *AppState) redrawGraphsOnResize(tcell.Screen) {
+	if state.graphRender == nil || state.graphWidth() == state.graphRenderedWidth {
+		return
+	}
+	render := state.graphRender
+	go state.app.QueueUpdateDraw(func() {
+		// Other output may have replaced the graphs meanwhile
+		if state.graphRender == nil {
+			return
+		}
+		row, column := state.secondSection.GetScrollOffset()
+		state.showGraphs(render)
+		state.secondSection.ScrollTo(row, column)
+	})
+}
+
+// flatSeriesOptions gives a series whose points are all equal a Y range
+// centered on its value. The charts otherwise range from 0 to the value,
+// drawing the line along the top edge, with labels too close to tell apart.
+func flatSeriesOptions(data []float64) []timeserieslinechart.Option {
+	if len(data) == 0 || slices.Min(data) != slices.Max(data) {
+		return nil
+	}
+	value := data[0]
+	margin := math.Max(math.Abs(value)*0.1, 1)
+	minY := value - margin
+	if value >= 0 {
+		minY = math.Max(minY, 0)
+	}
+	return []timeserieslinechart.Option{timeserieslinechart.WithYRange(minY, value+margin)}
+}
+
+func (state *AppState) plotCPUGraph(cpuData []float64, caption string, step time.Duration, width int) string {
 	if len(cpuData) == 0 {
 		return "No data available to plot."
 	}
 
 	// Increase the height for better Y-axis resolution
-	tslc := timeserieslinechart.New(80, 20) // Width: 80, Height: 20
+	tslc := timeserieslinechart.New(width, 20, flatSeriesOptions(cpuData)...)
 
 	endTime := time.Now()
-	step := time.Minute
 	startTime := endTime.Add(-time.Duration(len(cpuData)-1) * step)
 
 	tslc.XLabelFormatter = timeserieslinechart.HourTimeLabelFormatter()
@@ -136,16 +607,15 @@ func (state *AppState) plotCPUGraph(cpuData []float64, caption string) string {
 	return result
 }
 
-func (state *AppState) plotMemoryGraph(memData []float64, caption string) string {
+func (state *AppState) plotMemoryGraph(memData []float64, caption string, step time.Duration, width int) string {
 	if len(memData) == 0 {
 		return "No data available to plot."
 	}
 
 	// Create a new TimeSeriesLineChart with the desired width and height
-	tslc := timeserieslinechart.New(80, 10) // Adjust width and height as needed
+	tslc := timeserieslinechart.New(width, 10, flatSeriesOptions(memData)...)
 
 	endTime := time.Now()
-	step := time.Minute
 	startTime := endTime.Add(-time.Duration(len(memData)-1) * step)
 
 	tslc.XLabelFormatter = timeserieslinechart.HourTimeLabelFormatter()
@@ -169,3 +639,74 @@ func (state *AppState) plotMemoryGraph(memData []float64, caption string) string
 
 	return result
 }
+
+// plotNetworkGraph draws received and transmitted throughput as two stacked
+// charts, with the Y axis labeled in bytes per second.
+func (state *AppState) plotNetworkGraph(rxData, txData []float64, caption string, step time.Duration, width int) string {
+	if len(rxData) == 0 && len(txData) == 0 {
+		return fmt.Sprintf("%s\nNo data available to plot.", caption)
+	}
+
+	plot := func(data []float64, label string) string {
+		if len(data) == 0 {
+			return label + ": no data"
+		}
+
+		tslc := timeserieslinechart.New(width, 8, flatSeriesOptions(data)...)
+
+		endTime := time.Now()
+		startTime := endTime.Add(-time.Duration(len(data)-1) * step)
+
+		tslc.XLabelFormatter = timeserieslinechart.HourTimeLabelFormatter()
+		tslc.YLabelFormatter = func(_ int, value float64) string {
+			return formatBytes(int64(value)) + "/s"
+		}
+
+		for i, value := range data {
+			tslc.Push(timeserieslinechart.TimePoint{
+				Time:  startTime.Add(time.Duration(i) * step),
+				Value: value,
+			})
+		}
+		tslc.DrawBraille()
+
+		return fmt.Sprintf("%s\n%s", label, tslc.View())
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", caption, plot(rxData, "Received"), plot(txData, "Transmitted"))
+}
+
+// plotTimeSeries draws data, sampled every step up to now, as a line chart.
+func plotTimeSeries(data []float64, caption string, step time.Duration, width, height int) string {
+	if len(data) == 0 {
+		return "No data available to plot."
+	}
+
+	startTime := time.Now().Add(-time.Duration(len(data)-1) * step)
+	times := make([]time.Time, len(data))
+	for i := range data {
+		times[i] = startTime.Add(time.Duration(i) * step)
+	}
+	return plotTimePoints(times, data, caption, width, height)
+}
+
+// plotTimePoints draws data as a line chart, each value at its time in
+// times, for samples that aren't evenly spaced.
+func plotTimePoints(times []time.Time, data []float64, caption string, width, height int) string {
+	if len(data) == 0 {
+		return "No data available to plot."
+	}
+
+	tslc := timeserieslinechart.New(width, height, flatSeriesOptions(data)...)
+	tslc.XLabelFormatter = timeserieslinechart.HourTimeLabelFormatter()
+
+	for i, value := range data {
+		tslc.Push(timeserieslinechart.TimePoint{
+			Time:  times[i],
+			Value: value,
+		})
+	}
+	tslc.DrawBraille()
+
+	return fmt.Sprintf("%s\n%s", caption, tslc.View())
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	"github.com/prometheus/common/model"
+)
+
+func TestFlatSeriesOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []float64
+		minY, maxY float64
+	}{
+		{"positive", []float64{50, 50, 50}, 45, 55},
+		{"zero", []float64{0, 0}, 0, 1},
+		{"small", []float64{2, 2}, 1, 3},
+		{"negative", []float64{-20, -20, -20}, -22, -18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := flatSeriesOptions(tt.data)
+			if len(options) == 0 {
+				t.Fatalf("flatSeriesOptions(%v) returned no options", tt.data)
+			}
+			chart := timeserieslinechart.New(80, 20, options...)
+			if chart.MinY() != tt.minY || chart.MaxY() != tt.maxY {
+				t.Errorf("flatSeriesOptions(%v) Y range = [%v, %v], want [%v, %v]", tt.data, chart.MinY(), chart.MaxY(), tt.minY, tt.maxY)
+			}
+		})
+	}
+
+	for _, data := range [][]float64{nil, {1, 2, 1}} {
+		if options := flatSeriesOptions(data); options != nil {
+			t.Errorf("flatSeriesOptions(%v) = %d options, want none for a series that isn't flat", data, len(options))
+		}
+	}
+}
+
+func TestSumByTimestamp(t *testing.T) {
+	// Two containers, where the sidecar misses the scrape at t=30s
+	matrix := model.Matrix{
+		{
+			Metric: model.Metric{"container": "app"},
+			Values: []model.SamplePair{
+				{Timestamp: 0, Value: 100},
+				{Timestamp: 15000, Value: 110},
+				{Timestamp: 30000, Value: 120},
+			},
+		},
+		{
+			Metric: model.Metric{"container": "sidecar"},
+			Values: []model.SamplePair{
+				{Timestamp: 0, Value: 10},
+				{Timestamp: 15000, Value: 20},
+			},
+		},
+	}
+
+	data, last := sumByTimestamp(matrix)
+	if want := []float64{110, 130, 120}; !slices.Equal(data, want) {
+		t.Errorf("sumByTimestamp = %v, want %v", data, want)
+	}
+	if want := model.Time(30000).Time(); !last.Equal(want) {
+		t.Errorf("sumByTimestamp last sample = %v, want %v", last, want)
+	}
+
+	data, last = sumByTimestamp(nil)
+	if len(data) != 0 || !last.IsZero() {
+		t.Errorf("sumByTimestamp(nil) = %v, %v, want no data and a zero time", data, last)
+	}
+}
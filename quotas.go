@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// quotaUsageThreshold is the usage percentage above which a quota is
+// highlighted, as it's close to rejecting new pods.
+const quotaUsageThreshold = 80
+
+// showQuotas lists the ResourceQuotas of the highlighted namespace with how
+// much of each is used, and its LimitRanges.
+func (state *AppState) showQuotas() {
+	namespace := state.highlightedNamespace()
+	if namespace == "" {
+		state.setStatus("Highlight a namespace, or select one, to show its quotas", statusWarning)
+		return
+	}
+
+	label := namespace + "-quotas"
+	state.resetOutput(label)
+	state.secondSection.SetText(fmt.Sprintf("Loading quotas of namespace '%s'...", namespace))
+
+	cs := state.clientset
+	go func() {
+		quotas, err := cs.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+		var limitRanges *v1.LimitRangeList
+		if err == nil {
+			limitRanges, err = cs.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+		}
+		state.app.QueueUpdateDraw(func() {
+			if state.outputLabel != label {
+				return
+			}
+			if err != nil {
+				state.secondSection.SetText(fmt.Sprintf("%sError loading quotas: %s[-]", colorTag(state.theme.error), describeAPIError(err)))
+				return
+			}
+			state.secondSection.SetText(state.formatQuotas(namespace, quotas.Items, limitRanges.Items))
+			state.secondSection.ScrollToBeginning()
+			state.setFocusHighlight(state.secondSection)
+		})
+	}()
+}
+
+func (state *AppState) formatQuotas(namespace string, quotas []v1.ResourceQuota, limitRanges []v1.LimitRange) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[::b]Resource quotas of namespace %s[::-]\n", tview.Escape(namespace)))
+	if len(quotas) == 0 {
+		sb.WriteString("<none>\n")
+	}
+	for _, quota := range quotas {
+		sb.WriteString(fmt.Sprintf("\n%s%s[-]\n", colorTag(state.theme.value), tview.Escape(quota.Name)))
+		sb.WriteString(fmt.Sprintf("[::b]%-36s %-14s %-14s %s[::-]\n", "RESOURCE", "USED", "HARD", "USAGE"))
+		for _, name := range sortedResourceNames(quota.Status.Hard) {
+			hard := quota.Status.Hard[name]
+			used := quota.Status.Used[name]
+			sb.WriteString(fmt.Sprintf("%-36s %-14s %-14s %s\n", name, used.String(), hard.String(), state.formatQuotaUsage(used, hard)))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\nQuotas above %d%% usage are highlighted.\n", quotaUsageThreshold))
+
+	sb.WriteString(fmt.Sprintf("\n[::b]Limit ranges of namespace %s[::-]\n", tview.Escape(namespace)))
+	if len(limitRanges) == 0 {
+		sb.WriteString("<none>\n")
+	}
+	for _, limitRange := range limitRanges {
+		sb.WriteString(fmt.Sprintf("\n%s%s[-]\n", colorTag(state.theme.value), tview.Escape(limitRange.Name)))
+		sb.WriteString(fmt.Sprintf("[::b]%-22s %-18s %-10s %-10s %-16s %s[::-]\n", "TYPE", "RESOURCE", "MIN", "MAX", "DEFAULT REQUEST", "DEFAULT LIMIT"))
+		for _, limit := range limitRange.Spec.Limits {
+			names := make(v1.ResourceList)
+			for _, list := range []v1.ResourceList{limit.Min, limit.Max, limit.DefaultRequest, limit.Default} {
+				for name, quantity := range list {
+					names[name] = quantity
+				}
+			}
+			for _, name := range sortedResourceNames(names) {
+				sb.WriteString(fmt.Sprintf("%-22s %-18s %-10s %-10s %-16s %s\n", limit.Type, name,
+					quantityOrDash(limit.Min, name), quantityOrDash(limit.Max, name),
+					quantityOrDash(limit.DefaultRequest, name), quantityOrDash(limit.Default, name)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// formatQuotaUsage renders how much of hard is used as a percentage, in the
+// theme's error color above quotaUsageThreshold.
+func (state *AppState) formatQuotaUsage(used, hard resource.Quantity) string {
+	if hard.IsZero() {
+		if used.IsZero() {
+			return "-"
+		}
+		return colorTag(state.theme.error) + "over[-]"
+	}
+	percent := float64(used.MilliValue()) / float64(hard.MilliValue()) * 100
+	text := fmt.Sprintf("%.0f%%", percent)
+	if percent > quotaUsageThreshold {
+		return colorTag(state.theme.error) + text + "[-]"
+	}
+	return text
+}
+
+func sortedResourceNames(list v1.ResourceList) []v1.ResourceName {
+	names := make([]v1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func quantityOrDash(list v1.ResourceList, name v1.ResourceName) string {
+	if quantity, ok := list[name]; ok {
+		return quantity.String()
+	}
+	return "-"
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podSortMode is the order of the pods in the tree.
+type podSortMode string
+
+const (
+	sortByName   podSortMode = "name"
+	sortByCPU    podSortMode = "CPU"
+	sortByMemory podSortMode = "memory"
+)
+
+var podSortModes = []podSortMode{sortByName, sortByCPU, sortByMemory}
+
+func (state *AppState) cyclePodSort() {
+	for i, mode := range podSortModes {
+		if mode == state.podSort {
+			state.podSort = podSortModes[(i+1)%len(podSortModes)]
+			break
+		}
+	}
+
+	state.updateTreeTitle()
+}
+
+// sortPodsByUsage orders pods by descending CPU or memory usage, with one
+// metrics-server call for the whole namespace. Pods keep their name order
+// when sorting by name or when metrics are unavailable.
+func (state *AppState) sortPodsByUsage(ctx context.Context, namespace string, pods []v1.Pod) {
+	if state.podSort == sortByName {
+		return
+	}
+
+	state.mu.Lock()
+	mc := state.metricsClient
+	state.mu.Unlock()
+	if mc == nil {
+		return
+	}
+	metricsList, err := mc.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+
+	usage := make(map[string]int64, len(metricsList.Items))
+	for _, podMetrics := range metricsList.Items {
+		var total int64
+		for _, container := range podMetrics.Containers {
+			if state.podSort == sortByCPU {
+				total += container.Usage.Cpu().MilliValue()
+			} else {
+				total += container.Usage.Memory().Value()
+			}
+		}
+		usage[podMetrics.Name] = total
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		return usage[pods[i].Name] > usage[pods[j].Name]
+	})
+}
+
+// formatPodResources lists the CPU and memory requests and limits of each
+// container and their total, and how much of the limits the pod is using
+// when metrics is not nil.
+func (state *AppState) formatPodResources(pod *v1.Pod, metrics *PodMetrics) string {
+	value := colorTag(state.theme.value)
+	var sb strings.Builder
+	sb.WriteString("[::b]Requests / Limits:[::-]\n")
+
+	var cpuRequests, cpuLimits, memRequests, memLimits int64
+	cpuLimited, memLimited := true, true
+	for _, container := range pod.Spec.Containers {
+		resources := container.Resources
+		line := fmt.Sprintf("- %s: CPU %s%s[-] / %s%s[-], Memory %s%s[-] / %s%s[-]",
+			container.Name,
+			value, formatCPUQuantity(resources.Requests, v1.ResourceCPU), value, formatCPUQuantity(resources.Limits, v1.ResourceCPU),
+			value, formatMemoryQuantity(resources.Requests, v1.ResourceMemory), value, formatMemoryQuantity(resources.Limits, v1.ResourceMemory))
+		if usage, ok := metrics.container(container.Name); ok {
+			var utilization []string
+			if limit := resources.Limits.Cpu().MilliValue(); limit > 0 {
+				utilization = append(utilization, "CPU "+state.formatUtilization(usage.CPUMillicores, limit))
+			}
+			if limit := resources.Limits.Memory().Value(); limit > 0 {
+				utilization = append(utilization, "Memory "+state.formatUtilization(usage.MemoryBytes, limit))
+			}
+			if len(utilization) > 0 {
+				line += " (" + strings.Join(utilization, ", ") + " of limit)"
+			}
+		}
+		sb.WriteString(line + "\n")
+
+		cpuRequests += resources.Requests.Cpu().MilliValue()
+		memRequests += resources.Requests.Memory().Value()
+		if _, ok := resources.Limits[v1.ResourceCPU]; ok {
+			cpuLimits += resources.Limits.Cpu().MilliValue()
+		} else {
+			cpuLimited = false
+		}
+		if _, ok := resources.Limits[v1.ResourceMemory]; ok {
+			memLimits += resources.Limits.Memory().Value()
+		} else {
+			memLimited = false
+		}
+	}
+
+	cpuLimitText, memLimitText := "-", "-"
+	if cpuLimited {
+		cpuLimitText = fmt.Sprintf("%dm", cpuLimits)
+	}
+	if memLimited {
+		memLimitText = formatBytes(memLimits)
+	}
+	sb.WriteString(fmt.Sprintf("Total: CPU %s%dm[-] / %s%s[-], Memory %s%s[-] / %s%s[-]\n",
+		value, cpuRequests, value, cpuLimitText, value, formatBytes(memRequests), value, memLimitText))
+
+	// A limit only bounds the pod when every container sets one
+	if metrics != nil && cpuLimited && cpuLimits > 0 {
+		sb.WriteString(fmt.Sprintf("CPU Utilization: %s of limit\n", state.formatUtilization(metrics.CPUMillicores, cpuLimits)))
+	}
+	if metrics != nil && memLimited && memLimits > 0 {
+		sb.WriteString(fmt.Sprintf("Memory Utilization: %s of limit\n", state.formatUtilization(metrics.MemoryBytes, memLimits)))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func formatCPUQuantity(list v1.ResourceList, name v1.ResourceName) string {
+	quantity, ok := list[name]
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%dm", quantity.MilliValue())
+}
+
+func formatMemoryQuantity(list v1.ResourceList, name v1.ResourceName) string {
+	quantity, ok := list[name]
+	if !ok {
+		return "-"
+	}
+	return formatBytes(quantity.Value())
+}
+
+// formatUtilization renders usage as a percentage of limit, in the theme's
+// success color below 70%, warning below 90% and error above, where OOM
+// kills and throttling start.
+func (state *AppState) formatUtilization(usage, limit int64) string {
+	percent := float64(usage) / float64(limit) * 100
+	color := state.theme.success
+	switch {
+	case percent >= 90:
+		color = state.theme.error
+	case percent >= 70:
+		color = state.theme.warning
+	}
+	return fmt.Sprintf("%s%.0f%%[-]", colorTag(color), percent)
+}
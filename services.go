@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	servicesResource  = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	endpointsResource = schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}
+)
+
+// fetchServices lists the services in namespace through the dynamic client.
+func (state *AppState) fetchServices(ctx context.Context, namespace string, listOptions metav1.ListOptions) ([]v1.Service, error) {
+	list, err := state.dynamicClient.Resource(servicesResource).Namespace(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]v1.Service, 0, len(list.Items))
+	for _, item := range list.Items {
+		var service v1.Service
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), &service); err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+// newServiceNode creates the tree node for a service. Selecting it expands
+// the addresses of the pods backing the service.
+func (state *AppState) newServiceNode(service v1.Service) *tview.TreeNode {
+	text := fmt.Sprintf("%s %s [::d]%s[::-]", service.Name, service.Spec.Type, formatAge(service.CreationTimestamp.Time))
+	node := tview.NewTreeNode(text).SetReference(&service).SetColor(state.theme.success)
+	node.SetExpanded(false)
+	node.SetSelectedFunc(func() {
+		if node.IsExpanded() {
+			node.SetExpanded(false)
+			return
+		}
+		state.loadServiceEndpoints(node, &service)
+		node.SetExpanded(true)
+	})
+	return node
+}
+
+// loadServiceEndpoints replaces node's children with the addresses listed in
+// the service's Endpoints object.
+func (state *AppState) loadServiceEndpoints(node *tview.TreeNode, service *v1.Service) {
+	state.mu.Lock()
+	dc := state.dynamicClient
+	state.mu.Unlock()
+
+	state.loadChildren(node, func(ctx context.Context) []*tview.TreeNode {
+		item, err := dc.Resource(endpointsResource).Namespace(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+		if err != nil {
+			return []*tview.TreeNode{tview.NewTreeNode(fmt.Sprintf("Error fetching endpoints: %v", err)).SetColor(state.theme.error)}
+		}
+		var endpoints v1.Endpoints
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), &endpoints); err != nil {
+			return []*tview.TreeNode{tview.NewTreeNode(fmt.Sprintf("Error reading endpoints: %v", err)).SetColor(state.theme.error)}
+		}
+
+		var children []*tview.TreeNode
+		for _, subset := range endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				children = append(children, tview.NewTreeNode(endpointAddressText(address, subset.Ports)).SetColor(state.theme.success))
+			}
+			for _, address := range subset.NotReadyAddresses {
+				children = append(children, tview.NewTreeNode(endpointAddressText(address, subset.Ports)+" (not ready)").SetColor(state.theme.warning))
+			}
+		}
+		if len(children) == 0 {
+			return []*tview.TreeNode{tview.NewTreeNode("No endpoints").SetColor(state.theme.muted)}
+		}
+		return children
+	})
+}
+
+func endpointAddressText(address v1.EndpointAddress, ports []v1.EndpointPort) string {
+	text := address.IP
+	if len(ports) > 0 {
+		portNumbers := make([]string, 0, len(ports))
+		for _, port := range ports {
+			portNumbers = append(portNumbers, fmt.Sprintf("%d", port.Port))
+		}
+		text += ":" + strings.Join(portNumbers, ",")
+	}
+	if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+		text += " → " + address.TargetRef.Name
+	}
+	return text
+}
+
+func formatServiceDetails(service *v1.Service) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]Service Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", service.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", service.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(service.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Type: [yellow]%s[-]\n", service.Spec.Type))
+	sb.WriteString(fmt.Sprintf("Cluster IP: [yellow]%s[-]\n", service.Spec.ClusterIP))
+	if len(service.Spec.ExternalIPs) > 0 {
+		sb.WriteString(fmt.Sprintf("External IPs: [yellow]%s[-]\n", strings.Join(service.Spec.ExternalIPs, ", ")))
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		address := ingress.IP
+		if address == "" {
+			address = ingress.Hostname
+		}
+		sb.WriteString(fmt.Sprintf("Load Balancer: [yellow]%s[-]\n", address))
+	}
+	if service.Spec.ExternalName != "" {
+		sb.WriteString(fmt.Sprintf("External Name: [yellow]%s[-]\n", service.Spec.ExternalName))
+	}
+	sb.WriteString(fmt.Sprintf("Selector: [yellow]%s[-]\n", metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector})))
+
+	sb.WriteString("\n[::b]Ports:[::-]\n")
+	for _, port := range service.Spec.Ports {
+		name := port.Name
+		if name == "" {
+			name = "-"
+		}
+		line := fmt.Sprintf("- %s: [yellow]%d/%s[-] → %s", name, port.Port, port.Protocol, port.TargetPort.String())
+		if port.NodePort != 0 {
+			line += fmt.Sprintf(" (node port %d)", port.NodePort)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\nPress Enter on the service to list its endpoints.\n")
+	return sb.String()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/util/homedir"
+)
+
+type statusLevel int
+
+const (
+	statusInfo statusLevel = iota
+	statusSuccess
+	statusWarning
+	statusError
+)
+
+// setStatus shows msg in the status bar, colored by its level. It must run
+// on the UI goroutine.
+func (state *AppState) setStatus(msg string, level statusLevel) {
+	state.statusMessage = msg
+	state.statusLevel = level
+	state.updateStatusBar()
+}
+
+// updateStatusBar redraws the status bar: the latest message on the left,
+// followed by the current context, namespace and last refresh time.
+func (state *AppState) updateStatusBar() {
+	if state.statusBar == nil {
+		return
+	}
+
+	message := ""
+	if state.statusMessage != "" {
+		color := tcell.ColorDefault
+		switch state.statusLevel {
+		case statusSuccess:
+			color = state.theme.success
+		case statusWarning:
+			color = state.theme.warning
+		case statusError:
+			color = state.theme.error
+		}
+		message = fmt.Sprintf("%s%s[-] | ", colorTag(color), tview.Escape(state.statusMessage))
+	}
+
+	// Say which cluster this is, to avoid acting on the wrong one
+	value := colorTag(state.theme.value)
+	cluster := ""
+	if state.serverHost != "" {
+		cluster += fmt.Sprintf(" | Server: %s%s[-]", value, tview.Escape(state.serverHost))
+	}
+	if origin := state.contextOrigins[state.selectedContext]; origin != "" {
+		cluster += fmt.Sprintf(" | Kubeconfig: %s%s[-]", value, tview.Escape(shortenHome(origin)))
+	}
+
+	state.statusBar.SetText(fmt.Sprintf("%sContext: %s%s[-]%s | Namespace: %s%s[-] | Last refresh: %s%s[-]",
+		message, value, tview.Escape(state.selectedContext), cluster, value, tview.Escape(state.selectedNamespace), value, state.lastRefreshed))
+}
+
+// shortenHome replaces the home directory at the start of path with "~".
+func shortenHome(path string) string {
+	home := homedir.HomeDir()
+	if home == "" || !strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return path
+	}
+	return "~" + path[len(home):]
+}
+
+// finishRefresh records the outcome of a tree refresh in the status bar. It
+// must run on the UI goroutine.
+func (state *AppState) finishRefresh(err error) {
+	if err != nil {
+		state.logger.Error("refreshing the tree", "err", err)
+		state.setStatus(fmt.Sprintf("Refresh failed: %v", err), statusError)
+		return
+	}
+	state.lastRefreshed = time.Now().Format("15:04:05")
+	if state.statusLevel == statusError {
+		state.statusMessage = ""
+	}
+	state.updateStatusBar()
+}
+
+// spinnerFrames animate the tree while Podminator connects to the cluster.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// showConnecting animates the tree root until the Kubernetes clients are
+// ready. It stops early when something else, like a kubeconfig error,
+// replaces the root.
+func (state *AppState) showConnecting() {
+	node := tview.NewTreeNode("Connecting to cluster…").SetColor(state.theme.warning)
+	state.treeView.SetRoot(node).SetCurrentNode(node)
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-state.k8sClientsReady:
+				state.app.QueueUpdateDraw(func() {
+					if state.treeView.GetRoot() == node {
+						node.SetText("Please select a namespace to load pods")
+					}
+				})
+				return
+			case <-ticker.C:
+				replaced := false
+				state.app.QueueUpdateDraw(func() {
+					if state.treeView.GetRoot() != node {
+						replaced = true
+						return
+					}
+					node.SetText(spinnerFrames[frame%len(spinnerFrames)] + " Connecting to cluster…")
+				})
+				if replaced {
+					return
+				}
+			}
+		}
+	}()
+}
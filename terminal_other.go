@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+func runInWindowsTerminal(command, shell string) error {
+	return fmt.Errorf("Windows terminals are only available on Windows")
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+func runInWindowsTerminal(command, shell string) error {
+	var shellLine string
+	if shell == "bash" {
+		shellLine = "bash.exe -c " + syscall.EscapeArg(command+"; exec bash")
+	} else {
+		shellLine = "powershell.exe -NoExit -Command " + syscall.EscapeArg(command)
+	}
+
+	// The command line is set verbatim so that Go's own argument escaping
+	// doesn't get in the way of cmd.exe and wt.exe parsing.
+	var cmd *exec.Cmd
+	if wt, err := exec.LookPath("wt.exe"); err == nil {
+		// Windows Terminal treats an unescaped ';' as a new-tab separator
+		cmd = exec.Command(wt)
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			CmdLine: "wt.exe new-tab " + strings.ReplaceAll(shellLine, ";", `\;`),
+		}
+	} else {
+		cmd = exec.Command("cmd.exe")
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			CmdLine: `cmd.exe /c start "podminator" ` + escapeCmdMetachars(shellLine),
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start terminal: %v", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// escapeCmdMetachars caret-escapes the characters cmd.exe would otherwise
+// interpret, leaving double-quoted sections untouched.
+func escapeCmdMetachars(s string) string {
+	var sb strings.Builder
+	inQuotes := false
+	for _, r := range s {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if !inQuotes && strings.ContainsRune("^&|<>()", r) {
+			sb.WriteRune('^')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// theme names the colors used across the UI by the role they play, so a
+// preset can swap them all at once.
+type theme struct {
+	rootNode      tcell.Color
+	namespaceNode tcell.Color
+	kindNode      tcell.Color
+	podNode       tcell.Color // pods whose phase has no color of its own
+	border        tcell.Color
+	focusBorder   tcell.Color
+	warning       tcell.Color
+	error         tcell.Color
+	success       tcell.Color
+	value         tcell.Color // values next to their field names
+	muted         tcell.Color // placeholders and finished things
+
+	// styles replaces tview's defaults for backgrounds and plain text
+	styles tview.Theme
+}
+
+var darkTheme = theme{
+	rootNode:      tcell.ColorGreen,
+	namespaceNode: tcell.ColorYellow,
+	kindNode:      tcell.ColorWhite,
+	podNode:       tcell.ColorWhite,
+	border:        tcell.ColorWhite,
+	focusBorder:   tcell.ColorBlue,
+	warning:       tcell.ColorYellow,
+	error:         tcell.ColorRed,
+	success:       tcell.ColorGreen,
+	value:         tcell.ColorYellow,
+	muted:         tcell.ColorGray,
+	styles:        tview.Styles,
+}
+
+var lightTheme = theme{
+	rootNode:      tcell.ColorDarkGreen,
+	namespaceNode: tcell.ColorDarkGoldenrod,
+	kindNode:      tcell.ColorBlack,
+	podNode:       tcell.ColorBlack,
+	border:        tcell.ColorGray,
+	focusBorder:   tcell.ColorDarkBlue,
+	warning:       tcell.ColorDarkOrange,
+	error:         tcell.ColorDarkRed,
+	success:       tcell.ColorDarkGreen,
+	value:         tcell.ColorDarkGoldenrod,
+	muted:         tcell.ColorDimGray,
+	styles: tview.Theme{
+		PrimitiveBackgroundColor:    tcell.ColorDefault,
+		ContrastBackgroundColor:     tcell.ColorLightSteelBlue,
+		MoreContrastBackgroundColor: tcell.ColorLightGray,
+		BorderColor:                 tcell.ColorGray,
+		TitleColor:                  tcell.ColorBlack,
+		GraphicsColor:               tcell.ColorGray,
+		PrimaryTextColor:            tcell.ColorBlack,
+		SecondaryTextColor:          tcell.ColorDarkGoldenrod,
+		TertiaryTextColor:           tcell.ColorDarkGreen,
+		InverseTextColor:            tcell.ColorWhite,
+		ContrastSecondaryTextColor:  tcell.ColorDarkSlateGray,
+	},
+}
+
+// Solarized colors, see https://ethanschoonover.com/solarized/
+var solarizedTheme = theme{
+	rootNode:      tcell.NewHexColor(0x859900),
+	namespaceNode: tcell.NewHexColor(0xb58900),
+	kindNode:      tcell.NewHexColor(0x93a1a1),
+	podNode:       tcell.NewHexColor(0x839496),
+	border:        tcell.NewHexColor(0x586e75),
+	focusBorder:   tcell.NewHexColor(0x268bd2),
+	warning:       tcell.NewHexColor(0xcb4b16),
+	error:         tcell.NewHexColor(0xdc322f),
+	success:       tcell.NewHexColor(0x859900),
+	value:         tcell.NewHexColor(0xb58900),
+	muted:         tcell.NewHexColor(0x586e75),
+	styles: tview.Theme{
+		PrimitiveBackgroundColor:    tcell.NewHexColor(0x002b36),
+		ContrastBackgroundColor:     tcell.NewHexColor(0x073642),
+		MoreContrastBackgroundColor: tcell.NewHexColor(0x586e75),
+		BorderColor:                 tcell.NewHexColor(0x586e75),
+		TitleColor:                  tcell.NewHexColor(0x93a1a1),
+		GraphicsColor:               tcell.NewHexColor(0x586e75),
+		PrimaryTextColor:            tcell.NewHexColor(0x839496),
+		SecondaryTextColor:          tcell.NewHexColor(0xb58900),
+		TertiaryTextColor:           tcell.NewHexColor(0x859900),
+		InverseTextColor:            tcell.NewHexColor(0x002b36),
+		ContrastSecondaryTextColor:  tcell.NewHexColor(0x2aa198),
+	},
+}
+
+// colorTag returns the dynamic color tag that switches the text to c, or
+// back to the default color when c isn't set.
+func colorTag(c tcell.Color) string {
+	if c == tcell.ColorDefault {
+		return "[-]"
+	}
+	return fmt.Sprintf("[#%06x]", c.Hex())
+}
+
+var themes = map[string]theme{
+	"dark":      darkTheme,
+	"light":     lightTheme,
+	"solarized": solarizedTheme,
+}
+
+// lookupTheme returns the preset with the given name.
+func lookupTheme(name string) (theme, error) {
+	if t, ok := themes[name]; ok {
+		return t, nil
+	}
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return theme{}, fmt.Errorf("unknown theme '%s', expected one of: %s", name, strings.Join(names, ", "))
+}
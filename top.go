@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// topPodsCount is how many pods the top pods view ranks.
+const topPodsCount = 20
+
+// topPodsLabel identifies the top pods view in the output section.
+const topPodsLabel = "top-pods"
+
+// podUsage is a pod's CPU and memory usage, summed over its containers.
+type podUsage struct {
+	namespace, name string
+	cpuUsage        int64
+	memUsage        int64
+}
+
+// showTopPods ranks the pods of the selected namespace, or of every
+// namespace, by CPU usage like kubectl top pods --sort-by=cpu. Showing it
+// again while it's displayed switches between CPU and memory.
+func (state *AppState) showTopPods() {
+	if state.outputLabel == topPodsLabel {
+		if state.topPodsSort == sortByCPU {
+			state.topPodsSort = sortByMemory
+		} else {
+			state.topPodsSort = sortByCPU
+		}
+	}
+	state.resetOutput(topPodsLabel)
+	state.secondSection.SetText("Loading pod metrics...")
+
+	namespace := metav1.NamespaceAll
+	if slices.Contains(state.namespaceNames, state.selectedNamespace) {
+		namespace = state.selectedNamespace
+	}
+	sortBy := state.topPodsSort
+	go func() {
+		pods, err := state.fetchPodUsage(namespace)
+		state.app.QueueUpdateDraw(func() {
+			if state.outputLabel != topPodsLabel {
+				return
+			}
+			if err != nil {
+				state.secondSection.SetText(fmt.Sprintf("[red]Error loading pod metrics: %s[-]", describeAPIError(err)))
+				return
+			}
+			state.secondSection.SetText(formatTopPods(pods, sortBy, namespace == metav1.NamespaceAll))
+			state.secondSection.ScrollToBeginning()
+			state.setFocusHighlight(state.secondSection)
+		})
+	}()
+}
+
+// fetchPodUsage lists the usage of every pod in namespace, or in every
+// namespace when it is empty, with a single metrics-server call.
+func (state *AppState) fetchPodUsage(namespace string) ([]podUsage, error) {
+	state.mu.Lock()
+	mc := state.metricsClient
+	state.mu.Unlock()
+	if mc == nil {
+		return nil, fmt.Errorf("metrics are unavailable, is metrics-server installed?")
+	}
+
+	metricsList, err := mc.MetricsV1beta1().PodMetricses(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]podUsage, 0, len(metricsList.Items))
+	for _, podMetrics := range metricsList.Items {
+		usage := podUsage{namespace: podMetrics.Namespace, name: podMetrics.Name}
+		for _, container := range podMetrics.Containers {
+			usage.cpuUsage += container.Usage.Cpu().MilliValue()
+			usage.memUsage += container.Usage.Memory().Value()
+		}
+		pods = append(pods, usage)
+	}
+	return pods, nil
+}
+
+// formatTopPods renders the topPodsCount pods using the most CPU or memory
+// as a ranked table, with the namespace column only when several are listed.
+func formatTopPods(pods []podUsage, sortBy podSortMode, withNamespace bool) string {
+	sort.Slice(pods, func(i, j int) bool {
+		if sortBy == sortByMemory {
+			return pods[i].memUsage > pods[j].memUsage
+		}
+		return pods[i].cpuUsage > pods[j].cpuUsage
+	})
+	if len(pods) > topPodsCount {
+		pods = pods[:topPodsCount]
+	}
+
+	cpuHeader, memHeader := "CPU", "MEMORY"
+	if sortBy == sortByMemory {
+		memHeader += " ▼"
+	} else {
+		cpuHeader += " ▼"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[::b]Top %d pods by %s[::-] (press 'm' again to sort by %s)\n\n", topPodsCount, sortBy, otherTopPodsSort(sortBy)))
+	if len(pods) == 0 {
+		sb.WriteString("No pod metrics found.\n")
+		return sb.String()
+	}
+	if withNamespace {
+		sb.WriteString(fmt.Sprintf("[::b]%-4s %-24s %-50s %-10s %s[::-]\n", "#", "NAMESPACE", "POD", cpuHeader, memHeader))
+	} else {
+		sb.WriteString(fmt.Sprintf("[::b]%-4s %-50s %-10s %s[::-]\n", "#", "POD", cpuHeader, memHeader))
+	}
+	for i, pod := range pods {
+		cpu := fmt.Sprintf("%dm", pod.cpuUsage)
+		if withNamespace {
+			sb.WriteString(fmt.Sprintf("%-4d %-24s %-50s %-10s %s\n", i+1, pod.namespace, pod.name, cpu, formatBytes(pod.memUsage)))
+		} else {
+			sb.WriteString(fmt.Sprintf("%-4d %-50s %-10s %s\n", i+1, pod.name, cpu, formatBytes(pod.memUsage)))
+		}
+	}
+	return sb.String()
+}
+
+func otherTopPodsSort(sortBy podSortMode) podSortMode {
+	if sortBy == sortByMemory {
+		return sortByCPU
+	}
+	return sortByMemory
+}
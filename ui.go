@@ -3,15 +3,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (state *AppState) initializeUI() {
+	// The theme's styles must be in place before any primitive is created
+	tview.Styles = state.theme.styles
+
 	// Initialize UI components
 	state.helperText = tview.NewTextView()
 	state.updateHelperText()
@@ -26,14 +34,20 @@ func (state *AppState) initializeUI() {
 	state.namespaceDropdown.SetOptions([]string{"Loading namespaces..."}, nil)
 	state.namespaceDropdown.SetDisabled(true)
 
+	state.kindDropdown = tview.NewDropDown()
+	state.kindDropdown.SetLabel("Kind: ")
+	state.kindDropdown.SetOptions(resourceKinds, nil)
+	state.kindDropdown.SetCurrentOption(0)
+	state.kindDropdown.SetSelectedFunc(state.kindSelectHandler)
+
 	state.searchInput = tview.NewInputField()
 	state.searchInput.SetLabel("Search: ")
 	state.searchInput.SetFieldWidth(30)
 
 	state.treeView = tview.NewTreeView()
 	state.treeView.SetBorder(true)
-	state.treeView.SetTitle("Namespaces and Pods")
-	rootNode := tview.NewTreeNode("Please select a namespace to load pods").SetColor(tcell.ColorYellow)
+	state.updateTreeTitle()
+	rootNode := tview.NewTreeNode("Please select a namespace to load pods").SetColor(state.theme.warning)
 	state.treeView.SetRoot(rootNode).SetCurrentNode(rootNode)
 
 	state.secondSection = tview.NewTextView()
@@ -42,23 +56,32 @@ func (state *AppState) initializeUI() {
 	state.secondSection.SetTextAlign(tview.AlignLeft)
 	state.secondSection.SetText("Output will be displayed here")
 
+	state.statusBar = tview.NewTextView()
+	state.statusBar.SetDynamicColors(true)
+	state.updateStatusBar()
+
 	state.modal = tview.NewModal()
 
 	state.grid = tview.NewGrid()
-	state.grid.SetRows(4, 1, 0)
+	state.grid.SetRows(4, 1, 0, 1)
 	state.grid.SetColumns(0, 0, 0)
 	state.grid.SetBorders(true)
 	state.grid.AddItem(state.helperText, 0, 0, 1, 3, 0, 0, false)
 	state.grid.AddItem(state.contextDropdown, 1, 0, 1, 1, 0, 0, false)
 	state.grid.AddItem(state.namespaceDropdown, 1, 1, 1, 1, 0, 0, false)
-	state.grid.AddItem(state.searchInput, 1, 2, 1, 1, 0, 0, false)
+	state.grid.AddItem(tview.NewFlex().
+		AddItem(state.kindDropdown, 20, 0, false).
+		AddItem(state.searchInput, 0, 1, false), 1, 2, 1, 1, 0, 0, false)
 	state.grid.AddItem(state.treeView, 2, 0, 1, 1, 0, 0, true)
 	state.grid.AddItem(state.secondSection, 2, 1, 1, 2, 0, 0, false)
+	state.grid.AddItem(state.statusBar, 3, 0, 1, 3, 0, 0, false)
 
 	state.pages = tview.NewPages()
 	state.pages.AddPage("main", state.grid, true, true)
 
 	state.app.SetRoot(state.pages, true)
+	state.app.EnableMouse(*state.mouse)
+	state.app.SetAfterDrawFunc(state.redrawGraphsOnResize)
 	state.setFocusHighlight(state.contextDropdown)
 
 	// Event handlers
@@ -67,15 +90,40 @@ func (state *AppState) initializeUI() {
 
 func (state *AppState) updateHelperText() {
 	prometheusStatus := "Not connected"
-	if state.promDetected {
+	if state.prometheusAPI() != nil {
 		prometheusStatus = "Connected"
 	}
+	metricsStatus := "Not installed"
+	if state.metricsAvailable() {
+		metricsStatus = "Available"
+	}
+	timestampsStatus := "Off"
+	if state.showTimestamps {
+		timestampsStatus = "On"
+	}
+
+	readOnlyStatus := ""
+	if *state.readOnly {
+		readOnlyStatus = " [red::b]READ-ONLY[-::-]"
+	}
+
+	refreshStatus := "Pods update live, press 'r' for a full refresh"
+	if *state.refreshInterval > 0 {
+		refreshStatus = fmt.Sprintf("Pods update live and are fully refreshed every %s", *state.refreshInterval)
+	}
+
+	state.mu.Lock()
+	portForwardStatus := ""
+	if state.portForwardDesc != "" {
+		portForwardStatus = fmt.Sprintf(" - Port-forward: [green]%s[-]", state.portForwardDesc)
+	}
+	state.mu.Unlock()
 
 	state.helperText.SetText(fmt.Sprintf(
-		"[::b]Podminator[::d] - Prometheus: %s\n"+
-			" [yellow]'o'[-] Toggle Terminals | [yellow]'l'[-] Logs | [yellow]'t'[-] Tail Logs | [yellow]'e'[-] Exec | [yellow]'E'[-] (SHIFT+e) Exec with custom command | [yellow]'i'[-] Info | [yellow]'y'[-] YAML | [yellow]'h'[-] Metrics Graphs | [yellow]'n'[-] Namespace | [yellow]'s'[-] Search | [yellow]'r'[-] Refresh | [yellow]'spacebar'[-] Jump to bottom (Pod output) | [yellow]'q'[-] Quit \n"+
-			"Pods are refreshed every 60 seconds - last timestamp: [yellow]%s[-]",
-		prometheusStatus, state.lastRefreshed)).
+		"[::b]Podminator[::d]%s - Prometheus: %s - Metrics-server: %s - Log timestamps: %s%s\n"+
+			"%s\n"+
+			"%s",
+		readOnlyStatus, prometheusStatus, metricsStatus, timestampsStatus, portForwardStatus, state.keyBindingsHelp(), refreshStatus)).
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 }
@@ -83,44 +131,53 @@ func (state *AppState) updateHelperText() {
 func (state *AppState) setFocusHighlight(focusedView tview.Primitive) {
 	state.app.SetFocus(focusedView)
 
-	// Reset all borders to white
+	// Reset all borders to the theme's border color
 	if state.treeView != nil {
-		state.treeView.SetBorderColor(tcell.ColorWhite)
+		state.treeView.SetBorderColor(state.theme.border)
 	}
 	if state.secondSection != nil {
-		state.secondSection.SetBorderColor(tcell.ColorWhite)
+		state.secondSection.SetBorderColor(state.theme.border)
 	}
 	if state.searchInput != nil {
-		state.searchInput.SetBorderColor(tcell.ColorWhite)
+		state.searchInput.SetBorderColor(state.theme.border)
 	}
 	if state.namespaceDropdown != nil {
-		state.namespaceDropdown.SetBorderColor(tcell.ColorWhite)
+		state.namespaceDropdown.SetBorderColor(state.theme.border)
 	}
 	if state.contextDropdown != nil {
-		state.contextDropdown.SetBorderColor(tcell.ColorWhite)
+		state.contextDropdown.SetBorderColor(state.theme.border)
+	}
+	if state.kindDropdown != nil {
+		state.kindDropdown.SetBorderColor(state.theme.border)
 	}
 
-	// Set the border color of the focused view to blue
+	// Highlight the border of the focused view
 	if focusedView == state.treeView {
-		state.treeView.SetBorderColor(tcell.ColorBlue)
+		state.treeView.SetBorderColor(state.theme.focusBorder)
 	} else if focusedView == state.secondSection {
-		state.secondSection.SetBorderColor(tcell.ColorBlue)
+		state.secondSection.SetBorderColor(state.theme.focusBorder)
 	} else if focusedView == state.searchInput {
-		state.searchInput.SetBorderColor(tcell.ColorBlue)
+		state.searchInput.SetBorderColor(state.theme.focusBorder)
 	} else if focusedView == state.namespaceDropdown {
-		state.namespaceDropdown.SetBorderColor(tcell.ColorBlue)
+		state.namespaceDropdown.SetBorderColor(state.theme.focusBorder)
 	} else if focusedView == state.contextDropdown {
-		state.contextDropdown.SetBorderColor(tcell.ColorBlue)
+		state.contextDropdown.SetBorderColor(state.theme.focusBorder)
+	} else if focusedView == state.kindDropdown {
+		state.kindDropdown.SetBorderColor(state.theme.focusBorder)
 	}
 }
 
 func (state *AppState) setupEventHandlers() {
-	// Debounce the search input changes
+	// Debounce the search input changes. The refresh runs off the UI
+	// goroutine, so typing on cancels it instead of waiting for it
 	debouncedUpdate := state.debounce(func() {
-		state.app.QueueUpdateDraw(func() {
-			err := state.updatePodTreeView(state.searchInput.GetText())
+		var searchQuery string
+		state.app.QueueUpdate(func() {
+			searchQuery = state.searchInput.GetText()
+		})
+		state.refreshTreeInBackground(searchQuery, func(err error) {
 			if err != nil {
-				// Handle error
+				state.secondSection.SetText(fmt.Sprintf("[red]%v[-]", err))
 			}
 		})
 	}, 300*time.Millisecond)
@@ -133,17 +190,25 @@ func (state *AppState) setupEventHandlers() {
 		if key == tcell.KeyEnter {
 			err := state.updatePodTreeView(state.searchInput.GetText())
 			if err != nil {
-				// Handle error
+				state.secondSection.SetText(fmt.Sprintf("[red]%v[-]", err))
 			}
 			state.setFocusHighlight(state.treeView)
 		}
 	})
 
 	state.treeView.SetChangedFunc(func(node *tview.TreeNode) {
-		state.handlePodSelection(node)
+		state.handleNodeSelection(node)
 	})
 
 	state.treeView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch state.actionFor(event) {
+		case actionPrevNamespace:
+			state.jumpToNamespace(-1)
+			return nil
+		case actionNextNamespace:
+			state.jumpToNamespace(1)
+			return nil
+		}
 		switch event.Key() {
 		case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight, tcell.KeyEnter:
 			return event
@@ -152,18 +217,57 @@ func (state *AppState) setupEventHandlers() {
 		}
 	})
 
+	// Clicking a section focuses it, so keep its border highlight in step
+	state.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if state.modalActive || action != tview.MouseLeftDown {
+			return event, action
+		}
+		x, y := event.Position()
+		views := []interface {
+			tview.Primitive
+			InRect(x, y int) bool
+		}{state.treeView, state.secondSection, state.searchInput, state.namespaceDropdown, state.contextDropdown, state.kindDropdown}
+		for _, view := range views {
+			if view.InRect(x, y) {
+				state.setFocusHighlight(view)
+				break
+			}
+		}
+		return event, action
+	})
+
 	state.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if state.modalActive {
 			return event
 		}
-		switch event.Rune() {
-		case 'c', 'C':
+		// Let the search field receive every character typed into it
+		if state.app.GetFocus() == state.searchInput && event.Key() == tcell.KeyRune {
+			return event
+		}
+		if *state.vimMode {
+			if translated, handled := state.vimKey(event); handled {
+				return translated
+			}
+		}
+		// While searching the output, n/N step through the matches instead
+		if state.app.GetFocus() == state.secondSection && state.outputSearchTerm != "" {
+			switch event.Rune() {
+			case 'n', 'N':
+				return event
+			}
+		}
+		keyAction := state.actionFor(event)
+		switch keyAction {
+		case actionContext:
 			state.setFocusHighlight(state.contextDropdown)
 			return nil
-		case 'n', 'N':
+		case actionNamespace:
 			state.setFocusHighlight(state.namespaceDropdown)
 			return nil
-		case 'o', 'O':
+		case actionKind:
+			state.setFocusHighlight(state.kindDropdown)
+			return nil
+		case actionToggleTerminal:
 			state.useNewTerminal = !state.useNewTerminal
 			if state.useNewTerminal {
 				state.secondSection.SetText("Output now in new terminal windows")
@@ -171,37 +275,155 @@ func (state *AppState) setupEventHandlers() {
 				state.secondSection.SetText("Output now in this window")
 			}
 			return nil
-		case 's', 'S':
+		case actionSearch:
 			state.setFocusHighlight(state.searchInput)
 			return nil
-		case 'r', 'R':
-			go func() {
-				err := state.updatePodTreeView(state.searchInput.GetText())
-				if err != nil {
-					// Handle error
-				}
-				state.lastRefreshed = time.Now().Format("15:04:05")
-				state.app.QueueUpdateDraw(func() {
-					state.updateHelperText()
-				})
-			}()
+		case actionRefresh:
+			go state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
 			return nil
-		case 'q', 'Q':
-			state.app.Stop()
+		case actionHelp:
+			state.showHelpModal()
+			return nil
+		case actionLogPanel:
+			state.showLogPanel()
+			return nil
+		case actionQuit:
+			state.confirmQuit()
 			return nil
 		}
 
 		switch state.app.GetFocus() {
-		case state.searchInput, state.namespaceDropdown, state.contextDropdown:
+		case state.searchInput, state.namespaceDropdown, state.contextDropdown, state.kindDropdown:
 			return event
 		}
 
+		if reason := state.unavailableReason(keyAction); reason != "" {
+			state.setStatus(fmt.Sprintf("'%s' is %s", keyAction, reason), statusWarning)
+			return nil
+		}
+
+		if keyAction == actionTimestamps {
+			state.showTimestamps = !state.showTimestamps
+			state.updateHelperText()
+			return nil
+		}
+
+		if keyAction == actionStopPortForward {
+			if state.stopPortForward() {
+				state.secondSection.SetText("Port-forward stopped.")
+				state.updateHelperText()
+			}
+			return nil
+		}
+
+		if keyAction == actionPhaseFilter {
+			state.cyclePhaseFilter()
+			go state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
+			return nil
+		}
+
+		if keyAction == actionGlobalSearch || keyAction == actionFuzzySearch {
+			if keyAction == actionGlobalSearch {
+				state.toggleGlobalSearch()
+			} else {
+				state.toggleFuzzySearch()
+			}
+			// Only a search is affected, so the tree stays as is without one
+			if state.searchInput.GetText() != "" {
+				go state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
+			}
+			return nil
+		}
+
+		if keyAction == actionSystemNamespaces {
+			state.toggleHiddenNamespaces()
+			go state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
+			return nil
+		}
+
+		if keyAction == actionExpandAll || keyAction == actionCollapseAll {
+			state.setAllNamespacesExpanded(keyAction == actionExpandAll)
+			return nil
+		}
+
+		if keyAction == actionScale {
+			state.promptScale()
+			return nil
+		}
+
+		if keyAction == actionFavorite {
+			state.toggleFavoriteNamespace()
+			return nil
+		}
+
+		if keyAction == actionTopPods {
+			state.showTopPods()
+			return nil
+		}
+
+		if keyAction == actionQuotas {
+			state.showQuotas()
+			return nil
+		}
+
+		if keyAction == actionNodeUsage {
+			state.showNodeOverview()
+			return nil
+		}
+
+		if keyAction == actionPromQL {
+			state.showInputModal("PromQL", "Query: ", state.lastPromQL, func(text string) {
+				query := strings.TrimSpace(text)
+				if query == "" {
+					return
+				}
+				state.lastPromQL = query
+				state.showPromQLGraph(query)
+			})
+			return nil
+		}
+
+		if keyAction == actionMetadata {
+			state.cycleMetadataMode()
+			if node := state.treeView.GetCurrentNode(); state.isPodHighlighted && node != nil {
+				state.handlePodSelection(node)
+			}
+			return nil
+		}
+
+		if keyAction == actionSort {
+			state.cyclePodSort()
+			go state.refreshTreeInBackground(state.searchInput.GetText(), state.finishRefresh)
+			return nil
+		}
+
+		if keyAction == actionSaveOutput {
+			state.saveOutput()
+			return nil
+		}
+
+		if keyAction == actionRevealSecret {
+			if currentNode := state.treeView.GetCurrentNode(); currentNode != nil {
+				if secret, ok := currentNode.GetReference().(*v1.Secret); ok {
+					state.secondSection.SetText(formatSecretDetails(secret, true))
+				}
+			}
+			return nil
+		}
+
+		if keyAction == actionStopTail {
+			if state.stopLogStream() {
+				fmt.Fprint(state.secondSection, "\n[yellow]Log stream stopped.[-]\n")
+			}
+			return nil
+		}
+
 		if state.isPodHighlighted {
 			currentNode := state.treeView.GetCurrentNode()
 			if currentNode != nil {
-				if podMeta, ok := currentNode.GetReference().(*metav1.PartialObjectMetadata); ok {
-					podName := podMeta.Name
-					podNamespace := podMeta.Namespace
+				if podRef, ok := currentNode.GetReference().(*v1.Pod); ok {
+					podName := podRef.Name
+					podNamespace := podRef.Namespace
 
 					pod, err := state.clientset.CoreV1().Pods(podNamespace).Get(context.TODO(), podName, metav1.GetOptions{})
 					if err != nil {
@@ -214,73 +436,157 @@ func (state *AppState) setupEventHandlers() {
 						state.secondSection.SetText(fmt.Sprintf("[red]Error fetching pod details: %v[-]", err))
 						return nil
 					}
-					containers := pod.Spec.Containers
-					switch event.Rune() {
-					case 'h':
-						if state.promDetected {
-							go func() {
-								cpuData, memData, err := state.getPrometheusMetrics(podName, podNamespace)
-								if err != nil {
-									state.app.QueueUpdateDraw(func() {
-										state.secondSection.SetText(fmt.Sprintf("Error fetching Prometheus metrics: %v", err))
-									})
-									return
-								}
-
-								// Generate graphs using ntcharts
-								cpuGraph := state.plotCPUGraph(cpuData, "CPU Usage (milicores)")
-								memGraph := state.plotMemoryGraph(memData, "Memory Usage (megabytes)")
-
-								// Combine the graphs
-								graphText := fmt.Sprintf("%s\n\n%s", cpuGraph, memGraph)
-
-								state.app.QueueUpdateDraw(func() {
-									state.secondSection.SetText(graphText)
-									state.setFocusHighlight(state.secondSection)
-								})
-							}()
-						} else {
-							state.secondSection.SetText("Prometheus Not detected")
-							state.setFocusHighlight(state.treeView)
-						}
-					case 'y', 'Y':
-						state.runYamlCommand(podName, podNamespace)
+					switch keyAction {
+					case actionGraphs:
+						state.showPrometheusGraphs(podName, podNamespace)
+					case actionGraphRange:
+						state.showInputModal("Graph range", "Range [step] (e.g. 6h or 24h 10m): ", state.graphRange.input(), func(text string) {
+							r, err := parseGraphRange(text)
+							if err != nil {
+								state.secondSection.SetText(fmt.Sprintf("[red]Invalid graph range '%s': %v[-]", text, err))
+								return
+							}
+							state.graphRange = r
+							state.showPrometheusGraphs(podName, podNamespace)
+						})
+						return nil
+					case actionYAML:
+						state.runYamlCommand(pod)
+						state.setFocusHighlight(state.secondSection)
+						return nil
+					case actionCopyValue:
+						state.showCopyFieldModal(pod)
+						return nil
+					case actionCopyCommand:
+						state.showCopyCommandModal(pod)
+						return nil
+					case actionDescribe:
+						state.runDescribeCommand(pod)
 						state.setFocusHighlight(state.secondSection)
 						return nil
-					case 'i', 'I':
-						state.runDescribeCommand(podName, podNamespace)
+					case actionEvents:
+						state.showPodEvents(pod)
 						state.setFocusHighlight(state.secondSection)
 						return nil
-					case 'l', 'L':
-						if len(containers) > 1 {
-							state.showContainerSelectionModal(podName, containers, func(containerName string) {
-								state.runLogsCommand(podName, podNamespace, containerName)
+					case actionLogs:
+						state.selectContainer(pod, true, true, func(containerName string) {
+							state.runLogsCommand(podName, podNamespace, containerName, logOptions{})
+							state.setFocusHighlight(state.secondSection)
+						})
+						return nil
+					case actionLogsSince:
+						state.showInputModal("Logs since", "Duration (e.g. 30m, 1h): ", "1h", func(text string) {
+							since, err := time.ParseDuration(strings.TrimSpace(text))
+							if err != nil || since <= 0 {
+								state.secondSection.SetText(fmt.Sprintf("[red]Invalid duration '%s': use a positive value like 30s, 15m or 2h[-]", text))
+								return
+							}
+							opts := logOptions{since: since}
+							state.selectContainer(pod, true, true, func(containerName string) {
+								state.runLogsCommand(podName, podNamespace, containerName, opts)
 								state.setFocusHighlight(state.secondSection)
 							})
-						} else {
-							state.runLogsCommand(podName, podNamespace, containers[0].Name)
+						})
+						return nil
+					case actionPreviousLogs:
+						opts := logOptions{previous: true}
+						state.selectContainer(pod, true, true, func(containerName string) {
+							state.runLogsCommand(podName, podNamespace, containerName, opts)
 							state.setFocusHighlight(state.secondSection)
-						}
+						})
+						return nil
+					case actionTail:
+						state.selectContainer(pod, true, true, func(containerName string) {
+							state.runTailLogsInTerminal(podName, podNamespace, containerName)
+						})
+						return nil
+					case actionTailHere:
+						state.selectContainer(pod, true, false, func(containerName string) {
+							state.streamLogs(podName, podNamespace, containerName)
+							state.setFocusHighlight(state.secondSection)
+						})
+						return nil
+					case actionDelete:
+						question := fmt.Sprintf("Delete pod '%s' in namespace '%s'?", podName, podNamespace)
+						state.showConfirmModal(state.withPreview(question, state.deleteCommand(pod)), "Delete", func() {
+							state.deletePod(podName, podNamespace)
+						})
+						return nil
+					case actionRestart:
+						go func() {
+							kind, name, err := state.podController(pod)
+							state.app.QueueUpdateDraw(func() {
+								if err != nil {
+									state.secondSection.SetText(fmt.Sprintf("[red]Error looking up the owner of pod '%s': %v[-]", podName, err))
+									return
+								}
+								if kind == "" {
+									state.secondSection.SetText(fmt.Sprintf("Pod '%s' is not managed by a Deployment, StatefulSet or DaemonSet, so there is nothing to restart.", podName))
+									return
+								}
+								question := fmt.Sprintf("Restart %s '%s' in namespace '%s'?", kind, name, podNamespace)
+								command := state.kubectlCommand("rollout", "restart", strings.ToLower(kind)+"/"+name, "--namespace="+podNamespace)
+								state.showConfirmModal(state.withPreview(question, command), "Restart", func() {
+									go func() {
+										err := state.restartWorkload(kind, name, podNamespace)
+										state.app.QueueUpdateDraw(func() {
+											if err != nil {
+												state.secondSection.SetText(fmt.Sprintf("[red]Error restarting %s '%s': %v[-]", kind, name, err))
+												return
+											}
+											state.secondSection.SetText(fmt.Sprintf("[green]%s '%s' is restarting.[-]", kind, name))
+										})
+									}()
+								})
+							})
+						}()
+						return nil
+					case actionPortForward:
+						state.showInputModal("Port-forward", "localPort:podPort ", "", func(text string) {
+							mapping, err := parsePortMapping(text)
+							if err != nil {
+								state.secondSection.SetText(fmt.Sprintf("[red]Invalid port mapping: %v[-]", err))
+								return
+							}
+							state.startPortForward(podName, podNamespace, mapping)
+						})
 						return nil
-					case 't', 'T':
-						if len(containers) > 1 {
-							state.showContainerSelectionModal(podName, containers, func(containerName string) {
-								state.runTailLogsInTerminal(podName, podNamespace, containerName)
+					case actionCopyFiles:
+						state.showCopyModal(podName, func(localPath, podPath string, toPod bool) {
+							state.selectContainer(pod, false, false, func(containerName string) {
+								state.runCopyCommand(podName, podNamespace, containerName, localPath, podPath, toPod)
 							})
-						} else {
-							state.runTailLogsInTerminal(podName, podNamespace, containers[0].Name)
-						}
+						})
+						return nil
+					case actionExec:
+						state.selectContainer(pod, false, false, func(containerName string) {
+							state.execShell(podName, podNamespace, containerName)
+						})
 						return nil
-					case 'e':
-						if len(containers) > 1 {
-							state.showContainerSelectionModal(podName, containers, func(containerName string) {
-								state.runExecInTerminal(podName, podNamespace, containerName, "/bin/sh")
-								state.setFocusHighlight(state.treeView)
+					case actionDebug:
+						state.showInputModal("Debug container", "Image: ", defaultDebugImage, func(text string) {
+							image := strings.TrimSpace(text)
+							if image == "" {
+								return
+							}
+							state.selectContainer(pod, false, false, func(containerName string) {
+								command := state.kubectlCommand("debug", "-it", podName, "--namespace="+podNamespace, "--image="+image, "--target="+containerName)
+								state.previewCommand("Start a debug container? This is equivalent to:", "Start", command, func() {
+									state.startDebugContainer(podName, podNamespace, containerName, image)
+								})
 							})
-						} else {
-							state.runExecInTerminal(podName, podNamespace, containers[0].Name, "/bin/sh")
-							state.setFocusHighlight(state.treeView)
-						}
+						})
+						return nil
+					case actionExecCustom:
+						state.showInputModal("Exec", "Command: ", "/bin/bash", func(text string) {
+							command := strings.TrimSpace(text)
+							if command == "" {
+								return
+							}
+							state.selectContainer(pod, false, false, func(containerName string) {
+								state.runExecInTerminal(podName, podNamespace, containerName, command)
+							})
+						})
 						return nil
 					}
 				}
@@ -301,9 +607,24 @@ func (state *AppState) setupEventHandlers() {
 	state.secondSection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyRune:
-			if event.Rune() == ' ' {
+			switch event.Rune() {
+			case ' ':
 				state.secondSection.ScrollToEnd()
 				return nil
+			case '/':
+				state.showInputModal("Search output", "Find: ", state.outputSearchTerm, state.searchOutput)
+				return nil
+			case 'n':
+				state.jumpToOutputMatch(1)
+				return nil
+			case 'N':
+				state.jumpToOutputMatch(-1)
+				return nil
+			}
+		case tcell.KeyEscape:
+			if state.outputSearchTerm != "" {
+				state.searchOutput("")
+				return nil
 			}
 		case tcell.KeyLeft:
 			state.setFocusHighlight(state.treeView)
@@ -312,3 +633,208 @@ func (state *AppState) setupEventHandlers() {
 		return event
 	})
 }
+
+// resetOutput stops anything still writing to the output section and drops
+// the search state of its previous content, before new output replaces it.
+// label names the new output when it is saved to a file.
+func (state *AppState) resetOutput(label string) {
+	state.stopLogStream()
+	state.stopPodDetailsWatch()
+	state.stopOutputCommand()
+	state.outputLabel = label
+	state.graphRender = nil
+	state.outputSearchTerm = ""
+	state.outputMatchCount = 0
+	state.outputMatchIndex = 0
+}
+
+// searchOutput highlights every case-insensitive match of term in the output
+// section, keeping its colors. An empty term clears the highlights.
+func (state *AppState) searchOutput(term string) {
+	text, count := highlightMatches(state.secondSection.GetText(false), term)
+	state.outputSearchTerm = term
+	state.outputMatchCount = count
+	state.outputMatchIndex = 0
+	state.secondSection.SetText(text)
+	state.jumpToOutputMatch(0)
+}
+
+var (
+	// escapedTagPattern matches a tag escaped with tview.Escape, like
+	// "[red[]", which shows as "[red]".
+	escapedTagPattern = regexp.MustCompile(`^\[[^\[\]]+\[+\]`)
+	// styleTagPattern matches a color or region tag, which shows nothing.
+	styleTagPattern = regexp.MustCompile(`^\[("[^"\]]*"|[a-zA-Z0-9#:,;_.\-]+)\]`)
+	// matchRegionPattern matches the region tags added by highlightMatches.
+	matchRegionPattern = regexp.MustCompile(`^\[("match-\d+"|"")\]$`)
+)
+
+// outputSegment is a piece of tagged output: raw is how it is written and
+// text what it shows, empty for a tag.
+type outputSegment struct {
+	raw, text string
+}
+
+// splitTagged cuts tagged output into text, tags and escaped tags.
+func splitTagged(tagged string) []outputSegment {
+	var segments []outputSegment
+	for tagged != "" {
+		if tagged[0] == '[' {
+			if tag := escapedTagPattern.FindString(tagged); tag != "" {
+				segments = append(segments, outputSegment{raw: tag, text: tag[:len(tag)-2] + "]"})
+				tagged = tagged[len(tag):]
+				continue
+			}
+			if tag := styleTagPattern.FindString(tagged); tag != "" {
+				segments = append(segments, outputSegment{raw: tag})
+				tagged = tagged[len(tag):]
+				continue
+			}
+		}
+		end := strings.IndexByte(tagged[1:], '[') + 1
+		if end == 0 {
+			end = len(tagged)
+		}
+		segments = append(segments, outputSegment{raw: tagged[:end], text: tagged[:end]})
+		tagged = tagged[end:]
+	}
+	return segments
+}
+
+// highlightMatches wraps every case-insensitive match of term in the text
+// shown by tagged in a "match-N" region, leaving its color tags in place, and
+// returns the result with the number of regions. The regions of a previous
+// search are dropped first. A match can't split an escaped tag, so it covers
+// the whole tag instead.
+func highlightMatches(tagged, term string) (string, int) {
+	segments := splitTagged(tagged)
+	var matches [][]int
+	if term != "" {
+		var plain strings.Builder
+		for _, segment := range segments {
+			plain.WriteString(segment.text)
+		}
+		matches = regexp.MustCompile("(?i)"+regexp.QuoteMeta(term)).FindAllStringIndex(plain.String(), -1)
+	}
+
+	var sb strings.Builder
+	count, next, offset := 0, 0, 0
+	open := false
+	openRegion := func() {
+		sb.WriteString(fmt.Sprintf(`["match-%d"]`, count))
+		count++
+		open = true
+	}
+	closeRegion := func() {
+		sb.WriteString(`[""]`)
+		open = false
+	}
+
+	for _, segment := range segments {
+		if segment.text == "" {
+			if !matchRegionPattern.MatchString(segment.raw) {
+				sb.WriteString(segment.raw)
+			}
+			continue
+		}
+		end := offset + len(segment.text)
+
+		if segment.raw != segment.text {
+			if !open && next < len(matches) && matches[next][0] < end {
+				openRegion()
+			}
+			sb.WriteString(segment.raw)
+			for next < len(matches) && matches[next][1] <= end {
+				next++
+				if open {
+					closeRegion()
+				}
+			}
+			offset = end
+			continue
+		}
+
+		for pos := offset; pos < end; {
+			cut := end
+			if open {
+				cut = min(matches[next][1], end)
+			} else if next < len(matches) {
+				cut = min(max(matches[next][0], pos), end)
+			}
+			sb.WriteString(segment.raw[pos-offset : cut-offset])
+			pos = cut
+			switch {
+			case open && pos == matches[next][1]:
+				closeRegion()
+				next++
+			case !open && next < len(matches) && pos == matches[next][0] && pos < end:
+				openRegion()
+			}
+		}
+		offset = end
+	}
+	if open {
+		closeRegion()
+	}
+	return sb.String(), count
+}
+
+func (state *AppState) jumpToOutputMatch(delta int) {
+	if state.outputMatchCount == 0 {
+		return
+	}
+	state.outputMatchIndex = (state.outputMatchIndex + delta + state.outputMatchCount) % state.outputMatchCount
+	state.secondSection.Highlight(fmt.Sprintf("match-%d", state.outputMatchIndex))
+	state.secondSection.ScrollToHighlight()
+}
+
+// saveOutput writes the output section, without color tags, to a timestamped
+// file in the --output-dir directory.
+func (state *AppState) saveOutput() {
+	label := state.outputLabel
+	if label == "" {
+		label = "output"
+	}
+	fileName := fmt.Sprintf("podminator-%s-%s.txt", label, time.Now().Format("20060102-150405"))
+	path := filepath.Join(*state.outputDir, fileName)
+
+	err := os.WriteFile(path, []byte(state.secondSection.GetText(true)), 0644)
+	if err != nil {
+		state.setStatus(fmt.Sprintf("Failed to save output: %v", err), statusError)
+		return
+	}
+	state.setStatus(fmt.Sprintf("Output saved to %s", path), statusSuccess)
+}
+
+// backgroundOperations describes what quitting would stop.
+func (state *AppState) backgroundOperations() []string {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	var operations []string
+	if state.portForwardCmd != nil {
+		operations = append(operations, "port-forward "+state.portForwardDesc)
+	}
+	if state.logStreamCancel != nil {
+		operations = append(operations, "log stream "+state.logStreamDesc)
+	}
+	if state.copyCancel != nil {
+		operations = append(operations, "copy of "+state.copyDesc)
+	}
+	return operations
+}
+
+// confirmQuit stops the application, first asking for confirmation when
+// that would end a port-forward, a log stream or a copy, unless
+// --no-confirm-quit.
+func (state *AppState) confirmQuit() {
+	operations := state.backgroundOperations()
+	if *state.noConfirmQuit || len(operations) == 0 {
+		state.app.Stop()
+		return
+	}
+
+	text := "Quitting will stop:\n\n" + strings.Join(operations, "\n") + "\n\nQuit anyway?"
+	state.showChoiceModal(text, []string{"Quit"}, func(int) {
+		state.app.Stop()
+	})
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		name, tagged, term, want string
+		count                    int
+	}{
+		{"plain", "foo bar foo", "foo", `["match-0"]foo[""] bar ["match-1"]foo[""]`, 2},
+		{"keeps colors", "[red]Error[-]: error", "error", `[red]["match-0"]Error[""][-]: ["match-1"]error[""]`, 2},
+		{"across tags", "ab[green]cd[-]", "bc", `a["match-0"]b[green]c[""]d[-]`, 1},
+		{"escaped tag", "see [red[] here", "red", `see ["match-0"][red[][""] here`, 1},
+		{"previous search", `["match-0"]foo[""] foo`, "o f", `fo["match-0"]o f[""]oo`, 1},
+		{"cleared", `[red]["match-0"]foo[""][-]`, "", "[red]foo[-]", 0},
+		{"no match", "[yellow]foo[-]", "bar", "[yellow]foo[-]", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := highlightMatches(tt.tagged, tt.term)
+			if got != tt.want || count != tt.count {
+				t.Errorf("highlightMatches(%q, %q) = %q, %d, want %q, %d", tt.tagged, tt.term, got, count, tt.want, tt.count)
+			}
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 func detectTerminalProgram() string {
@@ -19,7 +26,102 @@ func detectTerminalProgram() string {
 	return termProgram
 }
 
-func runInTerminal(command string) error {
+func (state *AppState) runInTerminal(command string) error {
+	if *state.terminalCmd != "" {
+		return state.runInTerminalTemplate(command)
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return runInMacTerminal(command)
+	case "linux":
+		return runInLinuxTerminal(command)
+	case "windows":
+		return runInWindowsTerminal(command, *state.windowsShell)
+	default:
+		return fmt.Errorf("opening a new terminal is not supported on %s", runtime.GOOS)
+	}
+}
+
+// runInTerminalTemplate renders the --terminal-cmd template, substituting
+// {{.Command}} with the command quoted for the shell that runs the result.
+func (state *AppState) runInTerminalTemplate(command string) error {
+	tmpl, err := template.New("terminal-cmd").Parse(*state.terminalCmd)
+	if err != nil {
+		return fmt.Errorf("invalid --terminal-cmd template: %v", err)
+	}
+
+	var rendered strings.Builder
+	err = tmpl.Execute(&rendered, struct{ Command string }{Command: state.quoteForShell(command)})
+	if err != nil {
+		return fmt.Errorf("failed to render --terminal-cmd template: %v", err)
+	}
+
+	cmd := state.shellCommand(context.Background(), rendered.String())
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start terminal command: %v", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// shellQuote wraps s in single quotes so it survives as one POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// powershellQuote wraps s in single quotes so it survives as one PowerShell
+// word, where a quote inside is escaped by doubling it.
+func powershellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteForShell quotes s as one word for the shell shellCommand runs.
+func (state *AppState) quoteForShell(s string) string {
+	if runtime.GOOS == "windows" && *state.windowsShell != "bash" {
+		return powershellQuote(s)
+	}
+	return shellQuote(s)
+}
+
+// shellSafe matches the arguments that need no quoting, like pod names and
+// flags.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// kubectlFlags are the global kubectl flags that pin a command to the
+// selected context, and to the kubeconfig given with --kubeconfig, so it
+// reaches the cluster shown in the tree rather than kubeconfig's current
+// context. A new terminal may start elsewhere, so the kubeconfig path is
+// made absolute.
+func (state *AppState) kubectlFlags() []string {
+	var flags []string
+	if state.selectedContext != "" {
+		flags = append(flags, "--context="+state.selectedContext)
+	}
+	if *state.kubeconfig != "" {
+		path, err := filepath.Abs(*state.kubeconfig)
+		if err != nil {
+			path = *state.kubeconfig
+		}
+		flags = append(flags, "--kubeconfig="+path)
+	}
+	return flags
+}
+
+// kubectlCommand renders a kubectl command line for args, with kubectlFlags,
+// quoting the arguments the shell would otherwise split or expand.
+func (state *AppState) kubectlCommand(args ...string) string {
+	words := []string{"kubectl"}
+	for _, arg := range append(state.kubectlFlags(), args...) {
+		if !shellSafe.MatchString(arg) {
+			arg = shellQuote(arg)
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+func runInMacTerminal(command string) error {
 	terminalApp := detectTerminalProgram()
 	var appleScript string
 
@@ -42,16 +144,112 @@ func runInTerminal(command string) error {
 	return err
 }
 
-func runCommandAndDisplayOutput(command string, secondSection *tview.TextView) error {
-	cmd := exec.Command("bash", "-c", command)
-	output, err := cmd.CombinedOutput()
+// linuxTerminals lists the supported terminal emulators in lookup order,
+// along with the arguments that precede the command they should run.
+var linuxTerminals = []struct {
+	name string
+	args []string
+}{
+	{"gnome-terminal", []string{"--"}},
+	{"konsole", []string{"-e"}},
+	{"alacritty", []string{"-e"}},
+	{"kitty", []string{}},
+	{"xterm", []string{"-e"}},
+}
+
+func detectLinuxTerminal() (string, []string, error) {
+	if terminal := os.Getenv("TERMINAL"); terminal != "" {
+		path, err := exec.LookPath(terminal)
+		if err != nil {
+			return "", nil, fmt.Errorf("terminal %q from $TERMINAL not found: %v", terminal, err)
+		}
+		for _, t := range linuxTerminals {
+			if filepath.Base(path) == t.name {
+				return path, t.args, nil
+			}
+		}
+		return path, []string{"-e"}, nil
+	}
+
+	for _, t := range linuxTerminals {
+		if path, err := exec.LookPath(t.name); err == nil {
+			return path, t.args, nil
+		}
+	}
+
+	var names []string
+	for _, t := range linuxTerminals {
+		names = append(names, t.name)
+	}
+	return "", nil, fmt.Errorf("no supported terminal found (set $TERMINAL or install one of: %s)", strings.Join(names, ", "))
+}
+
+func runInLinuxTerminal(command string) error {
+	terminal, args, err := detectLinuxTerminal()
 	if err != nil {
 		return err
 	}
-	secondSection.SetText(string(output))
+
+	// Keep the window open once the command exits, like Terminal.app does
+	args = append(args, "bash", "-c", command+"; exec bash")
+	cmd := exec.Command(terminal, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %v", filepath.Base(terminal), err)
+	}
+	go cmd.Wait()
 	return nil
 }
 
+// shellCommand wraps command in the shell used for in-UI output. On Windows
+// this follows --windows-shell, everywhere else it is bash.
+func (state *AppState) shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" && *state.windowsShell != "bash" {
+		return exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-Command", command)
+	}
+	return exec.CommandContext(ctx, "bash", "-c", command)
+}
+
+// runCommandAndDisplayOutput runs command in the background, with a loading
+// placeholder in the output section, and shows its output once it's done.
+// onError formats the failure instead. Both get the output escaped, so it
+// shows as written. Replacing the output cancels the command.
+func (state *AppState) runCommandAndDisplayOutput(command string, onError func(err error) string, onSuccess func(output string) string) {
+	state.stopOutputCommand()
+	ctx, cancel := context.WithCancel(context.Background())
+	state.mu.Lock()
+	state.outputCommandCancel = cancel
+	state.mu.Unlock()
+
+	state.secondSection.SetText("Loading…")
+	go func() {
+		output, err := state.shellCommand(ctx, command).CombinedOutput()
+		state.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			state.stopOutputCommand()
+			// The output goes in as text, not as color or region tags
+			escaped := tview.Escape(string(output))
+			if err != nil {
+				state.secondSection.SetText(onError(fmt.Errorf("%v\n%s", err, escaped)))
+				return
+			}
+			state.secondSection.SetText(onSuccess(escaped))
+		})
+	}()
+}
+
+// stopOutputCommand cancels the command started by
+// runCommandAndDisplayOutput, if it's still running.
+func (state *AppState) stopOutputCommand() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.outputCommandCancel != nil {
+		state.outputCommandCancel()
+		state.outputCommandCancel = nil
+	}
+}
+
 func (state *AppState) debounce(f func(), delay time.Duration) func() {
 	var timer *time.Timer
 	return func() {
@@ -62,89 +260,508 @@ func (state *AppState) debounce(f func(), delay time.Duration) func() {
 	}
 }
 
-func (state *AppState) runYamlCommand(podName, podNamespace string) {
-	command := fmt.Sprintf("kubectl get pod %s --namespace=%s -o yaml", podName, podNamespace)
+func (state *AppState) runYamlCommand(pod *v1.Pod) {
+	state.resetOutput(pod.Name + "-yaml")
 	if state.useNewTerminal {
-		err := runInTerminal(command)
-		if err != nil {
-			// Handle error
-		}
-	} else {
-		err := runCommandAndDisplayOutput(command, state.secondSection)
+		err := state.runInTerminal(state.yamlCommand(pod))
 		if err != nil {
-			state.secondSection.SetText(fmt.Sprintf("Error running command: %v", err))
+			state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
 		}
+		return
 	}
+
+	data, err := podYAML(pod)
+	if err != nil {
+		state.secondSection.SetText(fmt.Sprintf("[red]Error rendering YAML: %v[-]", err))
+		return
+	}
+	state.secondSection.SetText(tview.Escape(string(data)))
+	state.secondSection.ScrollToBeginning()
+}
+
+func (state *AppState) yamlCommand(pod *v1.Pod) string {
+	return state.kubectlCommand("get", "pod", pod.Name, "--namespace="+pod.Namespace, "-o", "yaml")
 }
 
-func (state *AppState) runDescribeCommand(podName, podNamespace string) {
-	command := fmt.Sprintf("kubectl describe pod %s --namespace=%s", podName, podNamespace)
+// podYAML marshals pod like kubectl get -o yaml, without the managedFields
+// that would otherwise make up most of the output.
+func podYAML(pod *v1.Pod) ([]byte, error) {
+	pod = pod.DeepCopy()
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+	pod.ManagedFields = nil
+	return yaml.Marshal(pod)
+}
+
+func (state *AppState) describeCommand(pod *v1.Pod) string {
+	return state.kubectlCommand("describe", "pod", pod.Name, "--namespace="+pod.Namespace)
+}
+
+func (state *AppState) runDescribeCommand(pod *v1.Pod) {
+	state.resetOutput(pod.Name + "-describe")
 	if state.useNewTerminal {
-		err := runInTerminal(command)
+		err := state.runInTerminal(state.describeCommand(pod))
 		if err != nil {
-			// Handle error
+			state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
 		}
 	} else {
-		err := runCommandAndDisplayOutput(command, state.secondSection)
-		if err != nil {
-			state.secondSection.SetText(fmt.Sprintf("Error running command: %v", err))
+		state.showPodDescription(pod)
+	}
+}
+
+// runCopyCommand copies files to or from a container in the background,
+// reporting the outcome in the status bar. Unlike the commands showing their
+// output, moving through the tree doesn't cancel it, only quitting does.
+func (state *AppState) runCopyCommand(podName, podNamespace, containerName, localPath, podPath string, toPod bool) {
+	remote := fmt.Sprintf("%s/%s:%s", podNamespace, podName, podPath)
+	command := state.kubectlCommand("cp", remote, localPath, "-c", containerName)
+	description := fmt.Sprintf("%s to %s", remote, localPath)
+	if toPod {
+		command = state.kubectlCommand("cp", localPath, remote, "-c", containerName)
+		description = fmt.Sprintf("%s to %s", localPath, remote)
+	}
+
+	state.previewCommand("Run this command?", "Run", command, func() {
+		state.mu.Lock()
+		running := state.copyCancel != nil
+		state.mu.Unlock()
+		if running {
+			state.setStatus("Another copy is still running, wait for it to finish", statusWarning)
+			return
 		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		state.mu.Lock()
+		state.copyCancel = cancel
+		state.copyDesc = description
+		state.mu.Unlock()
+		state.setStatus("Copying "+description+"…", statusInfo)
+
+		go func() {
+			output, err := state.shellCommand(ctx, command).CombinedOutput()
+			cancelled := ctx.Err() != nil
+			state.stopCopy()
+			state.app.QueueUpdateDraw(func() {
+				switch {
+				case cancelled:
+					state.setStatus("Copy of "+description+" cancelled", statusWarning)
+				case err != nil:
+					state.logger.Error("copying files", "command", command, "err", err, "output", string(output))
+					state.setStatus(fmt.Sprintf("Error copying %s: %v: %s", description, err, strings.TrimSpace(string(output))), statusError)
+				default:
+					state.setStatus("Copied "+description, statusSuccess)
+				}
+			})
+		}()
+	})
+}
+
+// stopCopy cancels the copy started by runCopyCommand, if it's still
+// running.
+func (state *AppState) stopCopy() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.copyCancel != nil {
+		state.copyCancel()
+		state.copyCancel = nil
+		state.copyDesc = ""
 	}
 }
 
-func (state *AppState) runLogsCommand(podName, podNamespace, containerName string) {
-	command := fmt.Sprintf("kubectl logs %s --namespace=%s -c %s", podName, podNamespace, containerName)
+// logOptions holds the optional kubectl logs flags for a single invocation.
+type logOptions struct {
+	previous bool
+	since    time.Duration
+}
+
+// allContainersOption is offered by the container selection modal for log
+// commands, to read the logs of every container in the pod at once.
+const allContainersOption = "All containers"
+
+// logsContainerArgs selects containerName, or every container prefixed with
+// its name when allContainersOption was picked.
+func logsContainerArgs(containerName string) []string {
+	if containerName == allContainersOption {
+		return []string{"--all-containers=true", "--prefix"}
+	}
+	return []string{"-c", containerName}
+}
+
+// logsCommand builds the kubectl logs command for a container, with the
+// timestamps and tail length currently set.
+func (state *AppState) logsCommand(podName, podNamespace, containerName string, opts logOptions) string {
+	args := append([]string{"logs", podName, "--namespace=" + podNamespace}, logsContainerArgs(containerName)...)
+	if opts.previous {
+		args = append(args, "--previous")
+	}
+	if opts.since > 0 {
+		args = append(args, fmt.Sprintf("--since=%s", opts.since))
+	}
+	if state.showTimestamps {
+		args = append(args, "--timestamps")
+	}
+	if *state.tailLines > 0 {
+		args = append(args, fmt.Sprintf("--tail=%d", *state.tailLines))
+	}
+	return state.kubectlCommand(args...)
+}
+
+func (state *AppState) runLogsCommand(podName, podNamespace, containerName string, opts logOptions) {
+	state.resetOutput(podName + "-" + strings.ReplaceAll(strings.ToLower(containerName), " ", "-") + "-logs")
+	command := state.logsCommand(podName, podNamespace, containerName, opts)
 	if state.useNewTerminal {
-		err := runInTerminal(command)
+		err := state.runInTerminal(command)
 		if err != nil {
-			// Handle error
+			state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
 		}
 	} else {
-		err := runCommandAndDisplayOutput(command, state.secondSection)
-		if err != nil {
-			state.secondSection.SetText(fmt.Sprintf("Error running command: %v", err))
-		}
+		state.runCommandAndDisplayOutput(command, func(err error) string {
+			return fmt.Sprintf("Error running command: %v", err)
+		}, func(output string) string {
+			return output
+		})
 	}
 }
 
 func (state *AppState) runTailLogsInTerminal(podName, podNamespace, containerName string) {
-	command := fmt.Sprintf("kubectl logs -f %s --namespace=%s -c %s", podName, podNamespace, containerName)
-	err := runInTerminal(command)
+	args := append([]string{"logs", "-f", podName, "--namespace=" + podNamespace}, logsContainerArgs(containerName)...)
+	if state.showTimestamps {
+		args = append(args, "--timestamps")
+	}
+	err := state.runInteractiveCommand(state.kubectlCommand(args...))
 	if err != nil {
-		// Handle error
+		state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
 	}
 }
 
+// execShells are tried in order when opening a shell in a container.
+var execShells = []string{"/bin/bash", "/bin/sh"}
+
+// shellProbeTimeout bounds how long detecting a container's shell may take.
+const shellProbeTimeout = 10 * time.Second
+
+// detectShell returns the first of execShells that can run in the
+// container, or the last one when none can, so that kubectl reports the
+// actual problem in the terminal. kubectlFlags are passed in, as it runs
+// off the UI goroutine.
+func detectShell(kubectlFlags []string, podName, podNamespace, containerName string) string {
+	for _, shell := range execShells[:len(execShells)-1] {
+		ctx, cancel := context.WithTimeout(context.Background(), shellProbeTimeout)
+		args := append(append([]string{}, kubectlFlags...), "exec", podName, "--namespace="+podNamespace, "-c", containerName, "--", shell, "-c", "exit 0")
+		err := exec.CommandContext(ctx, "kubectl", args...).Run()
+		cancel()
+		if err == nil {
+			return shell
+		}
+	}
+	return execShells[len(execShells)-1]
+}
+
+// execShell opens the best shell available in the container, trying bash
+// before falling back to sh, and says which one was used.
+func (state *AppState) execShell(podName, podNamespace, containerName string) {
+	state.setStatus(fmt.Sprintf("Looking for a shell in %s/%s…", podName, containerName), statusInfo)
+	kubectlFlags := state.kubectlFlags()
+	go func() {
+		shell := detectShell(kubectlFlags, podName, podNamespace, containerName)
+		state.app.QueueUpdateDraw(func() {
+			command := state.execCommand(podName, podNamespace, containerName, shell)
+			state.previewCommand("Run this command?", "Run", command, func() {
+				if err := state.runInteractiveCommand(command); err != nil {
+					state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
+					state.setStatus(fmt.Sprintf("Could not open %s in %s/%s", shell, podName, containerName), statusError)
+					return
+				}
+				state.setStatus(fmt.Sprintf("Opened %s in %s/%s", shell, podName, containerName), statusSuccess)
+			})
+		})
+	}()
+}
+
 func (state *AppState) runExecInTerminal(podName, podNamespace, containerName, command string) {
-	fullCommand := fmt.Sprintf("kubectl exec -it %s --namespace=%s -c %s -- %s", podName, podNamespace, containerName, command)
-	err := runInTerminal(fullCommand)
+	fullCommand := state.execCommand(podName, podNamespace, containerName, command)
+	state.previewCommand("Run this command?", "Run", fullCommand, func() {
+		err := state.runInteractiveCommand(fullCommand)
+		if err != nil {
+			state.secondSection.SetText(fmt.Sprintf("Error opening terminal: %v", err))
+		}
+	})
+}
+
+func (state *AppState) deleteCommand(pod *v1.Pod) string {
+	return state.kubectlCommand("delete", "pod", pod.Name, "--namespace="+pod.Namespace)
+}
+
+// execCommand runs command, a command line left as typed, in the container.
+func (state *AppState) execCommand(podName, podNamespace, containerName, command string) string {
+	return state.kubectlCommand("exec", "-it", podName, "--namespace="+podNamespace, "-c", containerName, "--") + " " + command
+}
+
+// runInteractiveCommand opens command in a tmux pane when running inside
+// tmux, and in a new terminal window otherwise.
+func (state *AppState) runInteractiveCommand(command string) error {
+	if os.Getenv("TMUX") != "" && *state.tmuxMode != "off" {
+		return state.runInTmux(command)
+	}
+	return state.runInTerminal(command)
+}
+
+func (state *AppState) runInTmux(command string) error {
+	var args []string
+	if *state.tmuxMode == "window" {
+		args = []string{"new-window", command}
+	} else {
+		args = []string{"split-window", "-h", command}
+	}
+	output, err := exec.Command("tmux", args...).CombinedOutput()
 	if err != nil {
-		// Handle error
+		return fmt.Errorf("tmux: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// selectContainer runs commandFunc with the container of pod to act on,
+// straight away when there is only one and through the selection modal
+// otherwise. withInit also offers the init containers, for commands that
+// work on containers that have exited.
+func (state *AppState) selectContainer(pod *v1.Pod, withInit, includeAll bool, commandFunc func(containerName string)) {
+	containers := pod.Spec.Containers
+	if withInit && len(pod.Spec.InitContainers) > 0 {
+		containers = append(append([]v1.Container{}, pod.Spec.InitContainers...), containers...)
+	}
+	switch len(containers) {
+	case 0:
+		state.secondSection.SetText(fmt.Sprintf("[red]Pod '%s' has no containers to pick from.[-]", pod.Name))
+	case 1:
+		commandFunc(containers[0].Name)
+	default:
+		state.showContainerSelectionModal(pod.Name, containers, includeAll, commandFunc)
 	}
 }
 
-func (state *AppState) showContainerSelectionModal(podName string, containers []v1.Container, commandFunc func(containerName string)) {
+func (state *AppState) showContainerSelectionModal(podName string, containers []v1.Container, includeAll bool, commandFunc func(containerName string)) {
 	state.modal.ClearButtons()
 	var buttons []string
 	for _, container := range containers {
 		buttons = append(buttons, container.Name)
 	}
+	if includeAll {
+		buttons = append(buttons, allContainersOption)
+	}
 	buttons = append(buttons, "Cancel")
+	if len(containers) > maxContainerButtons {
+		state.showContainerSelectionList(podName, buttons, commandFunc)
+		return
+	}
 	state.modal = tview.NewModal().
 		SetText(fmt.Sprintf("Select a container for pod '%s':", podName)).
 		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			if buttonLabel != "Cancel" {
-				commandFunc(buttonLabel)
-			}
+			// Close first, so commandFunc can open a modal of its own
 			state.pages.RemovePage("containerModal")
 			state.modalActive = false
 			state.setFocusHighlight(state.treeView)
+			if buttonLabel != "Cancel" {
+				commandFunc(buttonLabel)
+			}
 		})
 	state.pages.AddPage("containerModal", state.modal, true, true)
 	state.modalActive = true
 }
 
+// maxContainerButtons is how many containers fit as buttons in the container
+// selection modal. Pods with more, like service mesh pods with sidecars, get
+// a list instead.
+const maxContainerButtons = 4
+
+// showContainerSelectionList offers the choices, the last being Cancel, as a
+// list to arrow through, and calls commandFunc with the one picked with
+// Enter. Esc cancels.
+func (state *AppState) showContainerSelectionList(podName string, choices []string, commandFunc func(containerName string)) {
+	done := func(choice string) {
+		state.pages.RemovePage("containerModal")
+		state.modalActive = false
+		state.setFocusHighlight(state.treeView)
+		if choice != "Cancel" {
+			commandFunc(choice)
+		}
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, choice := range choices {
+		list.AddItem(choice, "", 0, nil)
+	}
+	list.SetSelectedFunc(func(_ int, choice, _ string, _ rune) {
+		done(choice)
+	})
+	list.SetDoneFunc(func() {
+		done("Cancel")
+	})
+	list.SetBorder(true)
+	list.SetTitle(fmt.Sprintf(" Select a container for pod '%s' ", podName))
+
+	// Center the list on top of the main grid
+	layout := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, len(choices)+2, 1, true).
+			AddItem(nil, 0, 1, false), 60, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	state.pages.AddPage("containerModal", layout, true, true)
+	state.modalActive = true
+	state.app.SetFocus(list)
+}
+
+// showConfirmModal asks the user to confirm an action. Cancel is focused by
+// default so an accidental Enter never triggers it.
+func (state *AppState) showConfirmModal(text, confirmLabel string, onConfirm func()) {
+	previousFocus := state.app.GetFocus()
+	state.modal = tview.NewModal().
+		SetText(text).
+		AddButtons([]string{confirmLabel, "Cancel"}).
+		SetFocus(1).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			state.pages.RemovePage("confirmModal")
+			state.modalActive = false
+			state.setFocusHighlight(previousFocus)
+			if buttonLabel == confirmLabel {
+				onConfirm()
+			}
+		})
+	state.pages.AddPage("confirmModal", state.modal, true, true)
+	state.modalActive = true
+}
+
+// previewCommand runs run right away, or with --preview, shows question and
+// the kubectl command run performs, or is equivalent to when it calls the
+// API directly, and runs it once confirmed.
+func (state *AppState) previewCommand(question, confirmLabel, command string, run func()) {
+	if !*state.preview {
+		run()
+		return
+	}
+	state.showConfirmModal(question+"\n\n"+command, confirmLabel, run)
+}
+
+// withPreview adds the kubectl command equivalent to an action to the text
+// of its confirmation, with --preview.
+func (state *AppState) withPreview(text, command string) string {
+	if !*state.preview {
+		return text
+	}
+	return text + "\n\nEquivalent to:\n" + command
+}
+
+// showChoiceModal asks to pick one of choices, with a Cancel button after
+// them. onChoice runs after the modal is closed, with the index picked.
+func (state *AppState) showChoiceModal(text string, choices []string, onChoice func(index int)) {
+	previousFocus := state.app.GetFocus()
+	state.modal = tview.NewModal().
+		SetText(text).
+		AddButtons(append(append([]string{}, choices...), "Cancel")).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			state.pages.RemovePage("choiceModal")
+			state.modalActive = false
+			state.setFocusHighlight(previousFocus)
+			if buttonIndex >= 0 && buttonIndex < len(choices) {
+				onChoice(buttonIndex)
+			}
+		})
+	state.pages.AddPage("choiceModal", state.modal, true, true)
+	state.modalActive = true
+}
+
+// showInputModal prompts for a single line of text. onSubmit runs after the
+// modal is closed, with the entered text, only if the user confirms.
+func (state *AppState) showInputModal(title, label, initial string, onSubmit func(text string)) {
+	previousFocus := state.app.GetFocus()
+	closeModal := func() {
+		state.pages.RemovePage("inputModal")
+		state.modalActive = false
+		state.setFocusHighlight(previousFocus)
+	}
+
+	form := tview.NewForm()
+	form.AddInputField(label, initial, 40, nil, nil)
+	input := form.GetFormItem(0).(*tview.InputField)
+	submit := func() {
+		text := input.GetText()
+		closeModal()
+		onSubmit(text)
+	}
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			submit()
+		case tcell.KeyEscape:
+			closeModal()
+		}
+	})
+	form.AddButton("OK", submit)
+	form.AddButton("Cancel", closeModal)
+	form.SetCancelFunc(closeModal)
+	form.SetBorder(true)
+	form.SetTitle(title)
+
+	// Center the form on top of the main grid
+	layout := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 7, 1, true).
+			AddItem(nil, 0, 1, false), 70, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	state.pages.AddPage("inputModal", layout, true, true)
+	state.modalActive = true
+	state.app.SetFocus(form)
+}
+
+// showCopyModal prompts for the direction and paths of a kubectl cp.
+func (state *AppState) showCopyModal(podName string, onSubmit func(localPath, podPath string, toPod bool)) {
+	previousFocus := state.app.GetFocus()
+	closeModal := func() {
+		state.pages.RemovePage("copyModal")
+		state.modalActive = false
+		state.setFocusHighlight(previousFocus)
+	}
+
+	directions := []string{"Local -> Pod", "Pod -> Local"}
+	form := tview.NewForm()
+	form.AddDropDown("Direction: ", directions, 0, nil)
+	form.AddInputField("Local path: ", "", 50, nil, nil)
+	form.AddInputField("Pod path: ", "", 50, nil, nil)
+	form.AddButton("Copy", func() {
+		direction, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		localPath := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		podPath := strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText())
+		closeModal()
+		if localPath == "" || podPath == "" {
+			state.secondSection.SetText("[red]Both the local and the pod path are required.[-]")
+			return
+		}
+		onSubmit(localPath, podPath, direction == 0)
+	})
+	form.AddButton("Cancel", closeModal)
+	form.SetCancelFunc(closeModal)
+	form.SetBorder(true)
+	form.SetTitle(fmt.Sprintf("Copy files for pod '%s'", podName))
+
+	layout := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 11, 1, true).
+			AddItem(nil, 0, 1, false), 80, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	state.pages.AddPage("copyModal", layout, true, true)
+	state.modalActive = true
+	state.app.SetFocus(form)
+}
+
 func minMax(data []float64) (min, max float64) {
 	if len(data) == 0 {
 		return 0, 0
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+)
+
+// vimKey maps h/j/k/l to navigation in the tree and the output section when
+// --vim is set. It reports whether the key was handled, in which case the
+// returned event, possibly nil, replaces the original one.
+func (state *AppState) vimKey(event *tcell.EventKey) (*tcell.EventKey, bool) {
+	if event.Key() != tcell.KeyRune {
+		return event, false
+	}
+
+	switch state.app.GetFocus() {
+	case state.treeView:
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), true
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), true
+		case 'l':
+			// Expand the node, or step into the output once there is nothing to expand
+			node := state.treeView.GetCurrentNode()
+			if node == nil {
+				return nil, true
+			}
+			if _, isPod := node.GetReference().(*v1.Pod); isPod || node.IsExpanded() {
+				state.setFocusHighlight(state.secondSection)
+				return nil, true
+			}
+			return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), true
+		case 'h':
+			// Collapse the node, or move up to its parent when it is collapsed
+			node := state.treeView.GetCurrentNode()
+			if node == nil {
+				return nil, true
+			}
+			if node.IsExpanded() && len(node.GetChildren()) > 0 && node != state.treeView.GetRoot() {
+				return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), true
+			}
+			if parent := findParentNode(state.treeView.GetRoot(), node); parent != nil {
+				state.treeView.SetCurrentNode(parent)
+				state.handleNodeSelection(parent)
+			}
+			return nil, true
+		}
+	case state.secondSection:
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), true
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), true
+		case 'h':
+			state.setFocusHighlight(state.treeView)
+			return nil, true
+		case 'l':
+			return nil, true
+		}
+	}
+	return event, false
+}
+
+// findParentNode returns the parent of target in the tree under root.
+func findParentNode(root, target *tview.TreeNode) *tview.TreeNode {
+	if root == nil {
+		return nil
+	}
+	for _, child := range root.GetChildren() {
+		if child == target {
+			return root
+		}
+		if parent := findParentNode(child, target); parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
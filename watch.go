@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rivo/tview"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// startPodWatch watches the pods of the selected namespace, or of the whole
+// cluster in "all" mode, and applies every change to the tree as it
+// happens. Any previous watch is stopped first.
+func (state *AppState) startPodWatch() {
+	state.stopPodWatch()
+
+	namespace := state.selectedNamespace
+	if namespace == "all" {
+		namespace = metav1.NamespaceAll
+	}
+
+	state.mu.Lock()
+	cs := state.clientset
+	stop := make(chan struct{})
+	state.podWatchStop = stop
+	state.mu.Unlock()
+
+	options := []informers.SharedInformerOption{informers.WithNamespace(namespace)}
+	if *state.nodeFilter != "" {
+		options = append(options, informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = "spec.nodeName=" + *state.nodeFilter
+		}))
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(cs, 0, options...)
+	informer := factory.Core().V1().Pods().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			// The tree was just listed, so the initial adds carry no news
+			if pod, ok := obj.(*v1.Pod); ok && !isInInitialList {
+				state.app.QueueUpdateDraw(func() { state.applyPodChange(pod, false) })
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if pod, ok := newObj.(*v1.Pod); ok {
+				state.app.QueueUpdateDraw(func() { state.applyPodChange(pod, false) })
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*v1.Pod); ok {
+				state.app.QueueUpdateDraw(func() { state.applyPodChange(pod, true) })
+			}
+		},
+	})
+	factory.Start(stop)
+}
+
+// stopPodWatch stops the running pod watch, if any.
+func (state *AppState) stopPodWatch() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.podWatchStop != nil {
+		close(state.podWatchStop)
+		state.podWatchStop = nil
+	}
+}
+
+// applyPodChange updates, adds or removes the tree node of a single pod. It
+// must run on the UI goroutine.
+func (state *AppState) applyPodChange(pod *v1.Pod, deleted bool) {
+	if state.selectedKind != podsKind || state.selectedNamespace == "Select a namespace" {
+		return
+	}
+	root := state.treeView.GetRoot()
+	if root == nil {
+		return
+	}
+
+	matches, err := state.podMatchesSearch(pod, state.searchInput.GetText())
+	if err != nil {
+		state.logger.Debug("matching a watched pod against the search", "pod", pod.Name, "err", err)
+		return
+	}
+
+	var nsNode *tview.TreeNode
+	for _, child := range root.GetChildren() {
+		if child.GetReference() == nil && child.GetText() == pod.Namespace {
+			nsNode = child
+			break
+		}
+	}
+	if nsNode == nil {
+		if !deleted && matches {
+			// A namespace that had no matching pods needs its whole branch
+			go state.refreshTreeInBackground(state.searchInput.GetText(), func(error) {})
+		}
+		return
+	}
+	kindNode := nsNode.GetChildren()[0]
+
+	var podNode *tview.TreeNode
+	for _, child := range kindNode.GetChildren() {
+		if existing, ok := child.GetReference().(*v1.Pod); ok && existing.Name == pod.Name {
+			podNode = child
+			break
+		}
+	}
+
+	switch {
+	case deleted || !matches:
+		if podNode == nil {
+			return
+		}
+		if state.treeView.GetCurrentNode() == podNode {
+			state.treeView.SetCurrentNode(kindNode)
+		}
+		kindNode.RemoveChild(podNode)
+	case podNode != nil:
+		podNode.SetText(podNodeText(pod)).SetColor(state.podPhaseColor(pod.Status.Phase)).SetReference(pod)
+	case state.podSort != sortByName || state.isFuzzyQuery(state.searchInput.GetText()):
+		// Only a fresh listing knows where the pod goes by usage or match
+		// quality
+		go state.refreshTreeInBackground(state.searchInput.GetText(), func(error) {})
+	default:
+		insertSorted(kindNode, state.newPodNode(*pod), pod.Name)
+	}
+}
+
+// podMatchesSearch applies the same filters fetchNamespacesWithPods uses,
+// including a label selector query, to a single pod.
+func (state *AppState) podMatchesSearch(pod *v1.Pod, searchQuery string) (bool, error) {
+	if state.searchScope(searchQuery) == "all" && state.hidesNamespace(pod.Namespace) {
+		return false, nil
+	}
+	labelSelector, matchesName, err := parseSearchQuery(searchQuery, state.fuzzySearch)
+	if err != nil {
+		return false, err
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return false, err
+	}
+	if !selector.Matches(labels.Set(pod.Labels)) || !matchesName(pod.Name) {
+		return false, nil
+	}
+	return state.phaseFilter == "" || pod.Status.Phase == state.phaseFilter, nil
+}
+
+// insertSorted adds child under parent, keeping the children ordered by
+// name like a fresh listing would.
+func insertSorted(parent, child *tview.TreeNode, name string) {
+	children := parent.GetChildren()
+	index := len(children)
+	for i, existing := range children {
+		if obj, ok := existing.GetReference().(metav1.Object); ok && obj.GetName() > name {
+			index = i
+			break
+		}
+	}
+
+	updated := make([]*tview.TreeNode, 0, len(children)+1)
+	updated = append(updated, children[:index]...)
+	updated = append(updated, child)
+	updated = append(updated, children[index:]...)
+	parent.SetChildren(updated)
+}
+
+// watchPodDetails re-renders the details pane whenever the selected pod
+// changes, until another node is selected or the output is replaced. The
+// server ends watches from time to time, so the watch is re-established from
+// the last version seen; when that version has expired, the pod is fetched
+// again.
+func (state *AppState) watchPodDetails(pod *v1.Pod, metrics *PodMetrics) {
+	state.stopPodDetailsWatch()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	state.mu.Lock()
+	cs := state.clientset
+	state.podDetailsCancel = cancel
+	state.mu.Unlock()
+
+	label := state.outputLabel
+	show := func(updated *v1.Pod, deleted bool) {
+		state.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil || state.outputLabel != label {
+				return
+			}
+			if deleted {
+				state.secondSection.SetText(fmt.Sprintf("[red]Pod '%s' in namespace '%s' was deleted.[-]", updated.Name, updated.Namespace))
+				return
+			}
+			row, column := state.secondSection.GetScrollOffset()
+			state.secondSection.SetText(state.formatPodDetails(updated, metrics))
+			state.secondSection.ScrollTo(row, column)
+		})
+	}
+
+	go func() {
+		resourceVersion := pod.ResourceVersion
+		for ctx.Err() == nil {
+			var err error
+			resourceVersion, err = followPodDetails(ctx, cs, pod, resourceVersion, show)
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				var current *v1.Pod
+				err = withRetry(ctx, func() error {
+					var getErr error
+					current, getErr = cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+					return getErr
+				})
+				if apierrors.IsNotFound(err) {
+					show(pod, true)
+					return
+				}
+				if err == nil {
+					show(current, false)
+					resourceVersion = current.ResourceVersion
+				}
+			}
+			if err != nil && ctx.Err() == nil {
+				state.logger.Warn("watching the pod details", "pod", pod.Name, "namespace", pod.Namespace, "err", err)
+				return
+			}
+		}
+	}()
+}
+
+// followPodDetails watches pod from resourceVersion and passes every change
+// to show, until ctx is cancelled or the server ends the watch. It returns
+// the last resourceVersion seen, to resume from, and the error the watch
+// ended with, if any.
+func followPodDetails(ctx context.Context, cs kubernetes.Interface, pod *v1.Pod, resourceVersion string, show func(*v1.Pod, bool)) (string, error) {
+	var watcher watch.Interface
+	err := withRetry(ctx, func() error {
+		var watchErr error
+		watcher, watchErr = cs.CoreV1().Pods(pod.Namespace).Watch(ctx, metav1.ListOptions{
+			FieldSelector:       fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		return watchErr
+	})
+	if err != nil {
+		return resourceVersion, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			if event.Type == watch.Error {
+				return resourceVersion, apierrors.FromObject(event.Object)
+			}
+			updated, isPod := event.Object.(*v1.Pod)
+			if !isPod {
+				continue
+			}
+			resourceVersion = updated.ResourceVersion
+			if event.Type != watch.Bookmark {
+				show(updated, event.Type == watch.Deleted)
+			}
+		}
+	}
+}
+
+// stopPodDetailsWatch stops the watch started by watchPodDetails, if any.
+func (state *AppState) stopPodDetailsWatch() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.podDetailsCancel != nil {
+		state.podDetailsCancel()
+		state.podDetailsCancel = nil
+	}
+}
@@ -0,0 +1,521 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	podsKind         = "Pods"
+	deploymentsKind  = "Deployments"
+	statefulSetsKind = "StatefulSets"
+	daemonSetsKind   = "DaemonSets"
+	servicesKind     = "Services"
+	configMapsKind   = "ConfigMaps"
+	secretsKind      = "Secrets"
+	jobsKind         = "Jobs"
+	cronJobsKind     = "CronJobs"
+)
+
+// resourceKinds lists the resource types the tree can show, in the order
+// they appear in the kind dropdown.
+var resourceKinds = []string{podsKind, deploymentsKind, statefulSetsKind, daemonSetsKind, jobsKind, cronJobsKind, servicesKind, configMapsKind, secretsKind}
+
+func (state *AppState) kindSelectHandler(option string, index int) {
+	state.selectedKind = option
+	state.updateTreeTitle()
+	if state.selectedNamespace == "Select a namespace" {
+		return
+	}
+
+	err := state.updatePodTreeView(state.searchInput.GetText())
+	if err != nil {
+		state.secondSection.SetText(fmt.Sprintf("[red]%v[-]", err))
+	}
+	state.setFocusHighlight(state.treeView)
+}
+
+// fetchNamespacesWithResources builds the tree nodes for the selected
+// non-pod resource kind, grouped by namespace.
+func (state *AppState) fetchNamespacesWithResources(ctx context.Context, searchQuery string) (map[string][]*tview.TreeNode, error) {
+	namespacesWithNodes := make(map[string][]*tview.TreeNode)
+
+	labelSelector, matchesName, err := parseSearchQuery(searchQuery, state.fuzzySearch)
+	if err != nil {
+		return nil, err
+	}
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+
+	var mu sync.Mutex
+	err = state.listInNamespaces(ctx, state.selectedNamespace, func(namespace string) error {
+		var nodes []*tview.TreeNode
+		switch state.selectedKind {
+		case deploymentsKind:
+			list, err := state.clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, deployment := range list.Items {
+				if matchesName(deployment.Name) {
+					nodes = append(nodes, state.newDeploymentNode(deployment))
+				}
+			}
+		case statefulSetsKind:
+			list, err := state.clientset.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, statefulSet := range list.Items {
+				if matchesName(statefulSet.Name) {
+					desired := int32(1)
+					if statefulSet.Spec.Replicas != nil {
+						desired = *statefulSet.Spec.Replicas
+					}
+					node := state.newWorkloadNode(&statefulSet, &statefulSet.ObjectMeta, statefulSet.Status.ReadyReplicas, desired, statefulSet.Spec.Selector)
+					nodes = append(nodes, node)
+				}
+			}
+		case daemonSetsKind:
+			list, err := state.clientset.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, daemonSet := range list.Items {
+				if matchesName(daemonSet.Name) {
+					node := state.newWorkloadNode(&daemonSet, &daemonSet.ObjectMeta, daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled, daemonSet.Spec.Selector)
+					nodes = append(nodes, node)
+				}
+			}
+		case servicesKind:
+			services, err := state.fetchServices(ctx, namespace, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, service := range services {
+				if matchesName(service.Name) {
+					nodes = append(nodes, state.newServiceNode(service))
+				}
+			}
+		case jobsKind:
+			list, err := state.clientset.BatchV1().Jobs(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, job := range list.Items {
+				if matchesName(job.Name) {
+					nodes = append(nodes, state.newJobNode(job))
+				}
+			}
+		case cronJobsKind:
+			list, err := state.clientset.BatchV1().CronJobs(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, cronJob := range list.Items {
+				if matchesName(cronJob.Name) {
+					nodes = append(nodes, state.newCronJobNode(cronJob))
+				}
+			}
+		case configMapsKind:
+			list, err := state.clientset.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, configMap := range list.Items {
+				if matchesName(configMap.Name) {
+					node := state.newConfigNode(&configMap, configMap.Name, len(configMap.Data)+len(configMap.BinaryData), formatAge(configMap.CreationTimestamp.Time))
+					nodes = append(nodes, node)
+				}
+			}
+		case secretsKind:
+			list, err := state.clientset.CoreV1().Secrets(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, secret := range list.Items {
+				if matchesName(secret.Name) {
+					node := state.newConfigNode(&secret, secret.Name, len(secret.Data), formatAge(secret.CreationTimestamp.Time))
+					nodes = append(nodes, node)
+				}
+			}
+		}
+		if len(nodes) > 0 {
+			mu.Lock()
+			namespacesWithNodes[namespace] = nodes
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespacesWithNodes, nil
+}
+
+func (state *AppState) newDeploymentNode(deployment appsv1.Deployment) *tview.TreeNode {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return state.newWorkloadNode(&deployment, &deployment.ObjectMeta, deployment.Status.AvailableReplicas, desired, deployment.Spec.Selector)
+}
+
+// newWorkloadNode creates the tree node for a workload, showing its ready and
+// desired replicas. Selecting it expands the pods matched by selector.
+func (state *AppState) newWorkloadNode(workload interface{}, meta *metav1.ObjectMeta, ready, desired int32, selector *metav1.LabelSelector) *tview.TreeNode {
+	color := state.theme.success
+	if ready < desired {
+		color = state.theme.warning
+	}
+
+	text := fmt.Sprintf("%s %d/%d [::d]%s[::-]", meta.Name, ready, desired, formatAge(meta.CreationTimestamp.Time))
+	node := tview.NewTreeNode(text).SetReference(workload).SetColor(color)
+	node.SetExpanded(false)
+	node.SetSelectedFunc(func() {
+		if node.IsExpanded() {
+			node.SetExpanded(false)
+			return
+		}
+		state.loadSelectorPods(node, meta.Namespace, selector)
+		node.SetExpanded(true)
+	})
+	return node
+}
+
+// childrenLoadTimeout bounds the API calls that fill in an expanded node.
+const childrenLoadTimeout = 15 * time.Second
+
+// loadChildren shows a placeholder under node while load builds its children
+// off the UI goroutine, then swaps them in. A result is dropped when node has
+// been loaded again in the meantime.
+func (state *AppState) loadChildren(node *tview.TreeNode, load func(ctx context.Context) []*tview.TreeNode) {
+	placeholder := tview.NewTreeNode("Loading…").SetColor(state.theme.muted)
+	node.ClearChildren()
+	node.AddChild(placeholder)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), childrenLoadTimeout)
+		defer cancel()
+		children := load(ctx)
+		state.app.QueueUpdateDraw(func() {
+			if current := node.GetChildren(); len(current) != 1 || current[0] != placeholder {
+				return
+			}
+			node.SetChildren(children)
+		})
+	}()
+}
+
+// loadSelectorPods replaces node's children with the pods matching selector.
+func (state *AppState) loadSelectorPods(node *tview.TreeNode, namespace string, selector *metav1.LabelSelector) {
+	state.loadChildren(node, func(ctx context.Context) []*tview.TreeNode {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return []*tview.TreeNode{tview.NewTreeNode(fmt.Sprintf("Invalid selector: %v", err)).SetColor(state.theme.error)}
+		}
+		podList, err := state.fetchPodList(ctx, namespace, labelSelector.String())
+		if err != nil {
+			return []*tview.TreeNode{tview.NewTreeNode(fmt.Sprintf("Error listing pods: %v", err)).SetColor(state.theme.error)}
+		}
+		if len(podList.Items) == 0 {
+			return []*tview.TreeNode{tview.NewTreeNode("No pods").SetColor(state.theme.muted)}
+		}
+		children := make([]*tview.TreeNode, 0, len(podList.Items))
+		for _, pod := range podList.Items {
+			children = append(children, state.newPodNode(pod))
+		}
+		return children
+	})
+}
+
+// showWorkloadDetails renders the details of the selected workload. label
+// names the output when it is saved.
+func (state *AppState) showWorkloadDetails(label, details string) {
+	state.resetOutput(label)
+	state.isPodHighlighted = false
+	state.secondSection.SetText(details)
+}
+
+func formatDeploymentDetails(deployment *appsv1.Deployment) string {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[::b]Deployment Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", deployment.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", deployment.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(deployment.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Strategy: [yellow]%s[-]\n", deployment.Spec.Strategy.Type))
+	sb.WriteString(fmt.Sprintf("Selector: [yellow]%s[-]\n", metav1.FormatLabelSelector(deployment.Spec.Selector)))
+
+	sb.WriteString("\n[::b]Replicas:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Desired: [yellow]%d[-]\n", desired))
+	sb.WriteString(fmt.Sprintf("Updated: [yellow]%d[-]\n", deployment.Status.UpdatedReplicas))
+	sb.WriteString(fmt.Sprintf("Ready: [yellow]%d[-]\n", deployment.Status.ReadyReplicas))
+	sb.WriteString(fmt.Sprintf("Available: [yellow]%d[-]\n", deployment.Status.AvailableReplicas))
+
+	sb.WriteString("\n[::b]Containers:[::-]\n")
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-]\n", container.Name, container.Image))
+	}
+
+	if len(deployment.Status.Conditions) > 0 {
+		sb.WriteString("\n[::b]Conditions:[::-]\n")
+		for _, condition := range deployment.Status.Conditions {
+			sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-] %s\n", condition.Type, condition.Status, condition.Message))
+		}
+	}
+
+	sb.WriteString("\nPress Enter on the deployment to list its pods.\n")
+	return sb.String()
+}
+
+// podController resolves the workload that manages pod, following a
+// ReplicaSet up to its Deployment. It returns an empty kind for pods
+// without a restartable controller.
+func (state *AppState) podController(pod *v1.Pod) (kind, name string, err error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", "", nil
+	}
+
+	switch owner.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return owner.Kind, owner.Name, nil
+	case "ReplicaSet":
+		rs, err := state.clientset.AppsV1().ReplicaSets(pod.Namespace).Get(context.TODO(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", "", err
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			return rsOwner.Kind, rsOwner.Name, nil
+		}
+	}
+	return "", "", nil
+}
+
+// restartWorkload triggers a rolling restart the same way
+// `kubectl rollout restart` does, by stamping the pod template.
+func (state *AppState) restartWorkload(kind, name, namespace string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+		time.Now().Format(time.RFC3339)))
+
+	apps := state.clientset.AppsV1()
+	var err error
+	switch kind {
+	case "Deployment":
+		_, err = apps.Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = apps.StatefulSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = apps.DaemonSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("cannot restart a %s", kind)
+	}
+	return err
+}
+
+func formatStatefulSetDetails(statefulSet *appsv1.StatefulSet) string {
+	desired := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[::b]StatefulSet Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", statefulSet.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", statefulSet.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(statefulSet.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Service Name: [yellow]%s[-]\n", statefulSet.Spec.ServiceName))
+	sb.WriteString(fmt.Sprintf("Update Strategy: [yellow]%s[-]\n", statefulSet.Spec.UpdateStrategy.Type))
+	sb.WriteString(fmt.Sprintf("Pod Management: [yellow]%s[-]\n", statefulSet.Spec.PodManagementPolicy))
+	sb.WriteString(fmt.Sprintf("Selector: [yellow]%s[-]\n", metav1.FormatLabelSelector(statefulSet.Spec.Selector)))
+
+	sb.WriteString("\n[::b]Replicas:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Desired: [yellow]%d[-]\n", desired))
+	sb.WriteString(fmt.Sprintf("Current: [yellow]%d[-]\n", statefulSet.Status.CurrentReplicas))
+	sb.WriteString(fmt.Sprintf("Updated: [yellow]%d[-]\n", statefulSet.Status.UpdatedReplicas))
+	sb.WriteString(fmt.Sprintf("Ready: [yellow]%d[-]\n", statefulSet.Status.ReadyReplicas))
+
+	sb.WriteString("\n[::b]Containers:[::-]\n")
+	for _, container := range statefulSet.Spec.Template.Spec.Containers {
+		sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-]\n", container.Name, container.Image))
+	}
+
+	sb.WriteString("\nPress Enter on the statefulset to list its pods.\n")
+	return sb.String()
+}
+
+func formatDaemonSetDetails(daemonSet *appsv1.DaemonSet) string {
+	var sb strings.Builder
+	sb.WriteString("[::b]DaemonSet Information:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Name: [yellow]%s[-]\n", daemonSet.Name))
+	sb.WriteString(fmt.Sprintf("Namespace: [yellow]%s[-]\n", daemonSet.Namespace))
+	sb.WriteString(fmt.Sprintf("Age: [yellow]%s[-]\n", formatAge(daemonSet.CreationTimestamp.Time)))
+	sb.WriteString(fmt.Sprintf("Update Strategy: [yellow]%s[-]\n", daemonSet.Spec.UpdateStrategy.Type))
+	sb.WriteString(fmt.Sprintf("Selector: [yellow]%s[-]\n", metav1.FormatLabelSelector(daemonSet.Spec.Selector)))
+
+	sb.WriteString("\n[::b]Scheduling:[::-]\n")
+	sb.WriteString(fmt.Sprintf("Desired: [yellow]%d[-]\n", daemonSet.Status.DesiredNumberScheduled))
+	sb.WriteString(fmt.Sprintf("Current: [yellow]%d[-]\n", daemonSet.Status.CurrentNumberScheduled))
+	sb.WriteString(fmt.Sprintf("Updated: [yellow]%d[-]\n", daemonSet.Status.UpdatedNumberScheduled))
+	sb.WriteString(fmt.Sprintf("Ready: [yellow]%d[-]\n", daemonSet.Status.NumberReady))
+	sb.WriteString(fmt.Sprintf("Available: [yellow]%d[-]\n", daemonSet.Status.NumberAvailable))
+	sb.WriteString(fmt.Sprintf("Misscheduled: [yellow]%d[-]\n", daemonSet.Status.NumberMisscheduled))
+
+	sb.WriteString("\n[::b]Containers:[::-]\n")
+	for _, container := range daemonSet.Spec.Template.Spec.Containers {
+		sb.WriteString(fmt.Sprintf("- %s: [yellow]%s[-]\n", container.Name, container.Image))
+	}
+
+	sb.WriteString("\nPress Enter on the daemonset to list its pods.\n")
+	return sb.String()
+}
+
+// scaleTarget returns the workload to scale for ref, the reference of the
+// highlighted node: the Deployment or StatefulSet itself, or the one
+// managing the highlighted pod.
+func (state *AppState) scaleTarget(ref interface{}) (kind, name, namespace string, err error) {
+	switch ref := ref.(type) {
+	case *appsv1.Deployment:
+		return "Deployment", ref.Name, ref.Namespace, nil
+	case *appsv1.StatefulSet:
+		return "StatefulSet", ref.Name, ref.Namespace, nil
+	case *v1.Pod:
+		kind, name, err := state.podController(ref)
+		if err != nil {
+			return "", "", "", fmt.Errorf("looking up the owner of pod '%s': %v", ref.Name, err)
+		}
+		if kind != "Deployment" && kind != "StatefulSet" {
+			return "", "", "", fmt.Errorf("pod '%s' is not managed by a Deployment or StatefulSet", ref.Name)
+		}
+		return kind, name, ref.Namespace, nil
+	default:
+		return "", "", "", fmt.Errorf("highlight a Deployment, a StatefulSet or one of their pods to scale it")
+	}
+}
+
+// promptScale asks for the new replica count of the highlighted workload,
+// showing the current one, and scales it. The workload and its scale are
+// looked up off the UI goroutine.
+func (state *AppState) promptScale() {
+	node := state.treeView.GetCurrentNode()
+	if node == nil {
+		state.setStatus("Cannot scale: nothing is highlighted", statusWarning)
+		return
+	}
+	ref := node.GetReference()
+
+	go func() {
+		kind, name, namespace, err := state.scaleTarget(ref)
+		if err != nil {
+			state.app.QueueUpdateDraw(func() {
+				state.setStatus(fmt.Sprintf("Cannot scale: %v", err), statusWarning)
+			})
+			return
+		}
+		replicas, err := state.currentReplicas(kind, name, namespace)
+		state.app.QueueUpdateDraw(func() {
+			if err != nil {
+				state.setStatus(fmt.Sprintf("Error reading the scale of %s '%s': %v", kind, name, err), statusError)
+				return
+			}
+			state.showScaleModal(kind, name, namespace, replicas)
+		})
+	}()
+}
+
+// currentReplicas reads the replica count of a Deployment or StatefulSet
+// from its scale subresource.
+func (state *AppState) currentReplicas(kind, name, namespace string) (int32, error) {
+	apps := state.clientset.AppsV1()
+	switch kind {
+	case "Deployment":
+		scale, err := apps.Deployments(namespace).GetScale(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return scale.Spec.Replicas, nil
+	case "StatefulSet":
+		scale, err := apps.StatefulSets(namespace).GetScale(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return scale.Spec.Replicas, nil
+	}
+	return 0, fmt.Errorf("%s can't be scaled", kind)
+}
+
+// showScaleModal asks for the new replica count, starting from replicas.
+func (state *AppState) showScaleModal(kind, name, namespace string, replicas int32) {
+	state.showInputModal(fmt.Sprintf("Scale %s '%s'", kind, name), "Replicas: ", strconv.Itoa(int(replicas)), func(text string) {
+		replicas, err := strconv.ParseInt(strings.TrimSpace(text), 10, 32)
+		if err != nil || replicas < 0 {
+			state.setStatus(fmt.Sprintf("Invalid replica count '%s'", text), statusError)
+			return
+		}
+		command := state.kubectlCommand("scale", strings.ToLower(kind)+"/"+name, fmt.Sprintf("--replicas=%d", replicas), "--namespace="+namespace)
+		state.previewCommand(fmt.Sprintf("Scale %s '%s'? This is equivalent to:", kind, name), "Scale", command, func() {
+			state.runScale(kind, name, namespace, int32(replicas))
+		})
+	})
+}
+
+// runScale scales the workload in the background, then refreshes the tree
+// to show the pods coming and going.
+func (state *AppState) runScale(kind, name, namespace string, replicas int32) {
+	searchQuery := state.searchInput.GetText()
+	go func() {
+		err := state.scaleWorkload(kind, name, namespace, replicas)
+		if err != nil {
+			state.app.QueueUpdateDraw(func() {
+				state.setStatus(fmt.Sprintf("Error scaling %s '%s': %v", kind, name, err), statusError)
+			})
+			return
+		}
+		state.refreshTreeInBackground(searchQuery, func(refreshErr error) {
+			state.finishRefresh(refreshErr)
+			if refreshErr == nil {
+				state.setStatus(fmt.Sprintf("Scaled %s '%s' to %d replicas", kind, name, replicas), statusSuccess)
+			}
+		})
+	}()
+}
+
+// scaleWorkload sets the replica count through the scale subresource.
+func (state *AppState) scaleWorkload(kind, name, namespace string, replicas int32) error {
+	apps := state.clientset.AppsV1()
+	switch kind {
+	case "Deployment":
+		scale, err := apps.Deployments(namespace).GetScale(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		scale.Spec.Replicas = replicas
+		_, err = apps.Deployments(namespace).UpdateScale(context.TODO(), name, scale, metav1.UpdateOptions{})
+		return err
+	case "StatefulSet":
+		scale, err := apps.StatefulSets(namespace).GetScale(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		scale.Spec.Replicas = replicas
+		_, err = apps.StatefulSets(namespace).UpdateScale(context.TODO(), name, scale, metav1.UpdateOptions{})
+		return err
+	default:
+		return fmt.Errorf("cannot scale a %s", kind)
+	}
+}